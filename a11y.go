@@ -0,0 +1,132 @@
+package goq
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// implicitRoles maps element names to the ARIA role they have when no role
+// attribute is given. Elements whose role depends on their attributes are
+// handled by implicitRole.
+var implicitRoles = map[string]string{
+	"article":  "article",
+	"aside":    "complementary",
+	"button":   "button",
+	"dialog":   "dialog",
+	"fieldset": "group",
+	"footer":   "contentinfo",
+	"form":     "form",
+	"h1":       "heading",
+	"h2":       "heading",
+	"h3":       "heading",
+	"h4":       "heading",
+	"h5":       "heading",
+	"h6":       "heading",
+	"header":   "banner",
+	"hr":       "separator",
+	"li":       "listitem",
+	"main":     "main",
+	"menu":     "list",
+	"nav":      "navigation",
+	"ol":       "list",
+	"option":   "option",
+	"progress": "progressbar",
+	"section":  "region",
+	"table":    "table",
+	"tbody":    "rowgroup",
+	"td":       "cell",
+	"textarea": "textbox",
+	"tfoot":    "rowgroup",
+	"th":       "columnheader",
+	"thead":    "rowgroup",
+	"tr":       "row",
+	"ul":       "list",
+}
+
+// inputRoles maps input types to their implicit role, with anything else being
+// a textbox.
+var inputRoles = map[string]string{
+	"button":   "button",
+	"checkbox": "checkbox",
+	"image":    "button",
+	"number":   "spinbutton",
+	"radio":    "radio",
+	"range":    "slider",
+	"reset":    "button",
+	"search":   "searchbox",
+	"submit":   "button",
+}
+
+// implicitRole returns the ARIA role an element has by virtue of its name and
+// attributes, or an empty string if it has none.
+func implicitRole(s *goquery.Selection) string {
+	switch name := goquery.NodeName(s); name {
+	case "a", "area":
+		if hasAttr(s, "href") {
+			return "link"
+		}
+		return ""
+	case "img":
+		if alt, ok := s.Attr("alt"); ok && alt == "" {
+			return "presentation"
+		}
+		return "img"
+	case "input":
+		typ := strings.ToLower(s.AttrOr("type", "text"))
+		if typ == "hidden" {
+			return ""
+		}
+		if role, ok := inputRoles[typ]; ok {
+			return role
+		}
+		return "textbox"
+	case "select":
+		if hasAttr(s, "multiple") {
+			return "listbox"
+		}
+		return "combobox"
+	default:
+		return implicitRoles[name]
+	}
+}
+
+// roleVal returns the first role listed in the element's role attribute, or
+// else its implicit role.
+func roleVal(_ *Decoder, s *goquery.Selection) string {
+	if len(s.Nodes) == 0 {
+		return ""
+	}
+	s = s.First()
+	if roles := strings.Fields(s.AttrOr("role", "")); len(roles) > 0 {
+		return strings.ToLower(roles[0])
+	}
+	return implicitRole(s)
+}
+
+// accDescVal gives the accessible description of the first element: the text
+// of the elements its aria-describedby attribute refers to, joined by spaces,
+// or else its title attribute.
+func accDescVal(d *Decoder, s *goquery.Selection) string {
+	e := s.First()
+	var parts []string
+	if ids := strings.Fields(e.AttrOr("aria-describedby", "")); len(ids) > 0 {
+		byID := map[string]*goquery.Selection{}
+		rootSelection(e).Find("[id]").Each(func(_ int, el *goquery.Selection) {
+			if id := el.AttrOr("id", ""); byID[id] == nil {
+				byID[id] = el
+			}
+		})
+		for _, id := range ids {
+			if el, ok := byID[id]; ok {
+				if text := collapseSpace(d.text(el)); text != "" {
+					parts = append(parts, text)
+				}
+			}
+		}
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, " ")
+	}
+	return strings.TrimSpace(e.AttrOr("title", ""))
+}
@@ -0,0 +1,73 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rolePage = `<html><body>
+<nav id="nav"><a href="/">Home</a><a>Anchor</a></nav>
+<div id="tabs" role="TabList extra"></div>
+<input id="check" type="checkbox">
+<input id="search" type="search">
+<input id="plain">
+<img id="decor" src="x.png" alt="">
+<span id="none">Text</span>
+</body></html>`
+
+func TestRole(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Nav    string   `goquery:"#nav,role"`
+		Tabs   string   `goquery:"#tabs,role"`
+		Links  []string `goquery:"#nav a,role"`
+		Check  string   `goquery:"#check,role"`
+		Search string   `goquery:"#search,role"`
+		Plain  string   `goquery:"#plain,role"`
+		Decor  string   `goquery:"#decor,role"`
+		None   string   `goquery:"#none,role"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(rolePage), &a))
+	asrt.Equal("navigation", a.Nav)
+	asrt.Equal("tablist", a.Tabs)
+	asrt.Equal([]string{"link", ""}, a.Links)
+	asrt.Equal("checkbox", a.Check)
+	asrt.Equal("searchbox", a.Search)
+	asrt.Equal("textbox", a.Plain)
+	asrt.Equal("presentation", a.Decor)
+	asrt.Equal("", a.None)
+}
+
+const describedPage = `<html><body>
+<label for="pw">Password</label>
+<input id="pw" type="password" aria-describedby="pw-hint pw-rules missing">
+<p id="pw-hint">At least   12 characters.</p>
+<ul id="pw-rules">
+	<li>One digit</li>
+	<li>One symbol</li>
+</ul>
+<button id="save" title=" Saves your changes ">Save</button>
+<button id="both" aria-describedby="empty" title="Fallback">Go</button>
+<span id="empty"></span>
+<a id="plain" href="/">Home</a>
+</body></html>`
+
+func TestAccDesc(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Password string `goquery:"#pw,accdesc"`
+		Save     string `goquery:"#save,accdesc"`
+		Both     string `goquery:"#both,accdesc"`
+		Plain    string `goquery:"#plain,accdesc"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(describedPage), &a))
+	asrt.Equal("At least 12 characters. One digit One symbol", a.Password)
+	asrt.Equal("Saves your changes", a.Save)
+	asrt.Equal("Fallback", a.Both)
+	asrt.Equal("", a.Plain)
+}
@@ -0,0 +1,83 @@
+package goq
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	emailFormat = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s.]+$`)
+	telFormat   = regexp.MustCompile(`^\+?[0-9]{3,15}$`)
+)
+
+// telSeparators are the characters used to group the digits of a phone number,
+// which are removed from `tel` values.
+var telSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "", "/", "")
+
+// contactVal reads a phone number or email address from the first element of
+// the selection. The href of a `tel:` or `mailto:` link is preferred, without
+// its scheme or any parameters, and otherwise the value read by vf is used.
+// Phone numbers are stripped of their separators. The second result reports
+// whether the value has the basic format of its kind.
+func (d *Decoder) contactVal(s *goquery.Selection, vf valFunc, kind string) (string, bool) {
+	scheme, format := "tel:", telFormat
+	if kind == "email" {
+		scheme, format = "mailto:", emailFormat
+	}
+
+	href := strings.TrimSpace(s.First().AttrOr("href", ""))
+	var str string
+	if len(href) > len(scheme) && strings.EqualFold(href[:len(scheme)], scheme) {
+		str = href[len(scheme):]
+		str, _, _ = strings.Cut(str, "?")
+		if unescaped, err := url.PathUnescape(str); err == nil {
+			str = unescaped
+		}
+	} else {
+		str = vf(d, s)
+	}
+
+	if kind == "email" {
+		str, _, _ = strings.Cut(str, ",")
+		str = strings.TrimSpace(str)
+	} else {
+		str, _, _ = strings.Cut(str, ";")
+		str = telSeparators.Replace(strings.TrimSpace(str))
+	}
+	if str == "" {
+		return "", true
+	}
+	return str, format.MatchString(str)
+}
+
+// unmarshalContact sets v to the phone number or email address of the
+// selection. A value in the wrong format is left empty, or is an error if the
+// decoder is strict about contacts.
+func (d *Decoder) unmarshalContact(s *goquery.Selection, v reflect.Value, tag goqueryTag, kind string) error {
+	str, ok := d.contactVal(s, tag.valFunc(), kind)
+	if !ok {
+		if d.StrictContacts {
+			return &CannotUnmarshalError{
+				V:      v,
+				Reason: typeConversionError,
+				Err:    fmt.Errorf("%q is not a valid %s", str, kind),
+				Val:    str,
+			}
+		}
+		str = ""
+	}
+	if err := unmarshalLiteral(str, v); err != nil {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: typeConversionError,
+			Err:    err,
+			Val:    str,
+		}
+	}
+	return nil
+}
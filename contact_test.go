@@ -0,0 +1,64 @@
+package goq
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const contactPage = `<html><body>
+<div class="card">
+	<a class="phone" href="tel:+1-555-010-0199">Call us</a>
+	<a class="mail" href="MAILTO:sales%40example.com?subject=Hi">Email sales</a>
+	<span class="phone-text">(02) 9876 5432</span>
+	<span class="mail-text"> help@example.org </span>
+	<a class="bad-mail" href="mailto:nobody">Nobody</a>
+	<a class="multi" href="mailto:a@example.com,b@example.com">Both</a>
+	<a class="ext" href="tel:+44 20 7946 0018;ext=12">Office</a>
+</div>
+</body></html>`
+
+func TestContacts(t *testing.T) {
+	asrt := assert.New(t)
+
+	type card struct {
+		Phone     string `goquery:".phone,tel"`
+		Mail      string `goquery:".mail,email"`
+		PhoneText string `goquery:".phone-text,tel"`
+		MailText  string `goquery:".mail-text,email"`
+		BadMail   string `goquery:".bad-mail,email"`
+		Multi     string `goquery:".multi,email"`
+		Ext       string `goquery:".ext,tel"`
+		Missing   string `goquery:".missing,tel"`
+	}
+
+	var a card
+	asrt.NoError(Unmarshal([]byte(contactPage), &a))
+	asrt.Equal(card{
+		Phone:     "+15550100199",
+		Mail:      "sales@example.com",
+		PhoneText: "0298765432",
+		MailText:  "help@example.org",
+		Multi:     "a@example.com",
+		Ext:       "+442079460018",
+	}, a)
+
+	var b struct {
+		Phone   string `goquery:".phone,tel"`
+		BadMail string `goquery:".bad-mail,email"`
+	}
+	d := NewDecoder(strings.NewReader(contactPage))
+	d.StrictContacts = true
+	e := checkErr(asrt, d.Decode(&b))
+	asrt.Equal(typeConversionError, e.unwind().last().Reason)
+	asrt.Equal("nobody", e.unwind().last().Val)
+
+	var c struct {
+		Phone string `goquery:".phone,[class],tel"`
+	}
+	d = NewDecoder(strings.NewReader(contactPage))
+	d.StrictContacts = true
+	asrt.NoError(d.Decode(&c))
+	asrt.Equal("+15550100199", c.Phone)
+}
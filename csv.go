@@ -0,0 +1,83 @@
+package goq
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// csvFieldName normalizes a CSV header or struct field name for matching, so
+// that "Unit Price", "unit_price" and UnitPrice are the same column.
+func csvFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(name))
+}
+
+// unmarshalCSV appends a struct to the slice v for each record of the CSV held
+// in the value of the selection. The first record is the header, and columns
+// are matched to struct fields by name regardless of case, spaces, hyphens and
+// underscores. Columns without a field are ignored.
+func (d *Decoder) unmarshalCSV(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	eleT := v.Type().Elem()
+	structT := TypeDeref(eleT)
+	if structT.Kind() != reflect.Struct {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    fmt.Errorf("csv needs a slice of structs, not %s", v.Type()),
+		}
+	}
+
+	str := tag.valFunc()(d, s)
+	r := csv.NewReader(strings.NewReader(str))
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: typeConversionError,
+			Err:    err,
+			Val:    str,
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	fields := make([]int, len(records[0]))
+	for i, name := range records[0] {
+		fields[i] = -1
+		for j := 0; j < structT.NumField(); j++ {
+			f := structT.Field(j)
+			if f.IsExported() && csvFieldName(f.Name) == csvFieldName(name) {
+				fields[i] = j
+				break
+			}
+		}
+	}
+
+	for n, rec := range records[1:] {
+		newV := reflect.New(structT)
+		for i, val := range rec {
+			if fields[i] < 0 {
+				continue
+			}
+			if err := unmarshalLiteral(val, newV.Elem().Field(fields[i])); err != nil {
+				return &CannotUnmarshalError{
+					V:        v,
+					Reason:   typeConversionError,
+					Err:      err,
+					Val:      val,
+					FldOrIdx: n,
+				}
+			}
+		}
+		if eleT.Kind() != reflect.Ptr {
+			newV = newV.Elem()
+		}
+		v.Set(reflect.Append(v, newV))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const csvPage = `<html><body>
+<table><tr>
+	<td class="order">SKU,Qty,Unit Price,Note
+		A-1,2,9.50,"gift, wrapped"
+		B-7,3,1.25,</td>
+	<td class="ragged">SKU,Qty
+		A-1,2,extra</td>
+	<td class="badqty">SKU,Qty
+		A-1,two</td>
+</tr></table>
+</body></html>`
+
+type orderLine struct {
+	SKU       string
+	Qty       int
+	UnitPrice float64
+}
+
+func TestCSV(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Lines []orderLine  `goquery:".order,csv"`
+		Ptrs  []*orderLine `goquery:".order,csv"`
+		None  []orderLine  `goquery:".missing,csv"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(csvPage), &a))
+	asrt.Equal([]orderLine{
+		{SKU: "A-1", Qty: 2, UnitPrice: 9.5},
+		{SKU: "B-7", Qty: 3, UnitPrice: 1.25},
+	}, a.Lines)
+	asrt.Len(a.Ptrs, 2)
+	asrt.Equal("B-7", a.Ptrs[1].SKU)
+	asrt.Empty(a.None)
+
+	var b struct {
+		Lines []orderLine `goquery:".ragged,csv"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(csvPage), &b))
+	asrt.Equal(typeConversionError, e.unwind().last().Reason)
+
+	var c struct {
+		Lines []orderLine `goquery:".badqty,csv"`
+	}
+	e = checkErr(asrt, Unmarshal([]byte(csvPage), &c))
+	asrt.Equal(typeConversionError, e.unwind().last().Reason)
+	asrt.Equal("two", e.unwind().last().Val)
+}
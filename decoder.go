@@ -1,19 +1,99 @@
 package goq
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"net/url"
+	"strings"
 	"sync"
+	"text/template"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
 )
 
 // Decoder implements the same API you will see in encoding/xml and
 // encoding/json except that we do not currently support proper streaming
-// decoding as it is not supported by goquery upstream.
+// decoding as it is not supported by goquery upstream. See Stream for a
+// limited alternative suited to very large documents.
 type Decoder struct {
-	err   error
-	doc   *goquery.Document
-	cache sync.Map
+	// DefaultLocation is used to interpret times that do not specify a zone.
+	// If nil, such times are taken to be UTC.
+	DefaultLocation *time.Location
+
+	// Now, if set, gives the current time that `relativetime` values such as
+	// "3 days ago" are counted back from. If nil, time.Now is used.
+	Now func() time.Time
+
+	// BaseURL, if set, is used to resolve relative URLs, such as those
+	// unmarshaled into url.URL values.
+	BaseURL *url.URL
+
+	// Report, if set, is filled in with how each field was decoded.
+	Report *DecodeReport
+
+	// RootSelector, if set, limits decoding to the first element of the
+	// document that it matches, as if that element were the whole document.
+	// If it matches nothing, every field is left empty.
+	RootSelector string
+
+	// RootAll makes it an error for RootSelector to match more than one
+	// element, rather than using the first.
+	RootAll bool
+
+	// IgnoreTags lists the names of elements whose contents are left out of
+	// extracted text, such as "sup" for footnote markers.
+	IgnoreTags []string
+
+	// TrueFlagValues lists the attribute values, ignoring case, that
+	// `flag:<attribute>` reads as true. Any other value is false. If nil,
+	// DefaultTrueFlagValues is used.
+	TrueFlagValues []string
+
+	// ErrorPageSignals lists the phrases that mark a page as an error page for
+	// IsErrorPage and the `iserrorpage` value selector when found in its title
+	// or first heading. If nil, DefaultErrorPageSignals is used.
+	ErrorPageSignals []string
+
+	// StrictContacts makes it an error for a `tel` or `email` field to find a
+	// value that does not look like a phone number or email address, rather
+	// than leaving the field empty.
+	StrictContacts bool
+
+	// PreferVisible makes a scalar, time.Time or url.URL field whose selector
+	// matches several elements, such as the mobile and desktop copies of a
+	// price, leave out those hidden by the hidden attribute, aria-hidden or
+	// an inline display: none or visibility: hidden on them or an ancestor.
+	// If every match is hidden, all of them are read as usual. Values
+	// computed from every match, such as `countattr:`, `isunique`,
+	// `wordcount`, `charcount`, `join:` and `distinctcount`, always see
+	// every match.
+	PreferVisible bool
+
+	// StarConfig describes the markup of the ratings read by the `stars`
+	// value selector.
+	StarConfig StarConfig
+
+	// WordsPerMinute is the reading speed that `readingtime` estimates are
+	// based on. If zero, DefaultWordsPerMinute is used.
+	WordsPerMinute int
+
+	// WarnOnScriptStyleMatch makes it an error for a field to read the text
+	// of a <script> or <style> element, which usually means its selector
+	// is broader than intended. Fields reading a script with `jsonpath:` or
+	// `csv` are allowed.
+	WarnOnScriptStyleMatch bool
+
+	err       error
+	doc       *goquery.Document
+	cache     sync.Map
+	path      []string
+	filters   map[string]func(*goquery.Selection) bool
+	templates map[string]*template.Template
+	fields    map[string]bool
+	matched   map[*html.Node]int
 }
 
 // NewDecoder returns a new decoder given an io.Reader
@@ -23,6 +103,25 @@ func NewDecoder(r io.Reader) *Decoder {
 	return d
 }
 
+// RegisterFilter makes a predicate available to tags as `where:<name>`. Only
+// the matched elements for which the predicate returns true are unmarshaled.
+func (d *Decoder) RegisterFilter(name string, fn func(*goquery.Selection) bool) {
+	if d.filters == nil {
+		d.filters = map[string]func(*goquery.Selection) bool{}
+	}
+	d.filters[name] = fn
+}
+
+// RegisterTemplate makes a template available to tags as `tmpl:<name>`. The
+// template is executed with the matched *goquery.Selection as its data, and
+// its output is unmarshaled into the field.
+func (d *Decoder) RegisterTemplate(name string, t *template.Template) {
+	if d.templates == nil {
+		d.templates = map[string]*template.Template{}
+	}
+	d.templates[name] = t
+}
+
 // Decode will unmarshal the contents of the decoder when given an instance of
 // an annotated type as its argument. It will return any errors encountered
 // during either parsing the document or unmarshaling into the given object.
@@ -36,5 +135,52 @@ func (d *Decoder) Decode(dest interface{}) error {
 		}
 	}
 
-	return UnmarshalSelection(d.doc.Selection, dest)
+	root := d.doc.Selection
+	if d.RootSelector != "" {
+		root = d.doc.Find(d.RootSelector)
+		if d.RootAll && root.Length() > 1 {
+			return &CannotUnmarshalError{
+				Reason: multipleRoots,
+				Err:    fmt.Errorf("%q matched %d elements", d.RootSelector, root.Length()),
+			}
+		}
+		root = root.First()
+	}
+
+	return d.unmarshalSelection(root, dest)
+}
+
+// text returns the text of the selection, leaving out the contents of any
+// descendant elements named in IgnoreTags.
+func (d *Decoder) text(s *goquery.Selection) string {
+	if len(d.IgnoreTags) == 0 {
+		return s.Text()
+	}
+
+	var buf bytes.Buffer
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			buf.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && d.ignored(c.Data) {
+				continue
+			}
+			walk(c)
+		}
+	}
+	for _, n := range s.Nodes {
+		walk(n)
+	}
+	return buf.String()
+}
+
+func (d *Decoder) ignored(name string) bool {
+	for _, t := range d.IgnoreTags {
+		if strings.EqualFold(t, name) {
+			return true
+		}
+	}
+	return false
 }
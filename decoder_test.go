@@ -1,9 +1,12 @@
 package goq
 
 import (
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,3 +18,220 @@ func TestDecoder(t *testing.T) {
 	asrt.NoError(NewDecoder(strings.NewReader(hnPage)).Decode(&p))
 	asrt.Len(p.Items, 30)
 }
+
+const footnotePage = `<html><body>
+<p class="claim">Water boils at 100<sup>[1]</sup> degrees<sup><a href="#n2">[2]</a></sup>.</p>
+</body></html>`
+
+func TestIgnoreTags(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Claim string `goquery:".claim"`
+		Words int    `goquery:".claim,wordcount"`
+	}
+
+	d := NewDecoder(strings.NewReader(footnotePage))
+	d.IgnoreTags = []string{"SUP"}
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal("Water boils at 100 degrees.", a.Claim)
+	asrt.Equal(5, a.Words)
+
+	asrt.NoError(Unmarshal([]byte(footnotePage), &a))
+	asrt.Equal("Water boils at 100[1] degrees[2].", a.Claim)
+}
+
+const stockPage = `<html><body>
+<ul>
+	<li class="item" data-stock="3">Apple</li>
+	<li class="item" data-stock="0">Pear</li>
+	<li class="item" data-stock="12">Plum</li>
+</ul>
+</body></html>`
+
+func TestRegisterFilter(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		InStock []string `goquery:".item,where:inStock"`
+		All     []string `goquery:".item"`
+	}
+
+	d := NewDecoder(strings.NewReader(stockPage))
+	d.RegisterFilter("inStock", func(s *goquery.Selection) bool {
+		return s.AttrOr("data-stock", "0") != "0"
+	})
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal([]string{"Apple", "Plum"}, a.InStock)
+	asrt.Len(a.All, 3)
+
+	var b struct {
+		Items []string `goquery:".item,where:inStock"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(stockPage), &b)).unwind()
+	asrt.Equal(invalidModifier, e.last().Reason)
+	asrt.Contains(e.Error(), `"inStock"`)
+}
+
+const multiRootPage = `<html><body>
+<h1>Outside</h1>
+<article class="post"><h1>First</h1><p>One</p></article>
+<article class="post"><h1>Second</h1><p>Two</p></article>
+<aside><h1>Aside</h1></aside>
+</body></html>`
+
+func TestRootSelector(t *testing.T) {
+	asrt := assert.New(t)
+
+	type post struct {
+		Title  string   `goquery:"h1"`
+		Bodies []string `goquery:"p"`
+	}
+
+	var a post
+	d := NewDecoder(strings.NewReader(multiRootPage))
+	d.RootSelector = "aside"
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal("Aside", a.Title)
+	asrt.Empty(a.Bodies)
+
+	var b post
+	d = NewDecoder(strings.NewReader(multiRootPage))
+	d.RootSelector = ".post"
+	asrt.NoError(d.Decode(&b))
+	asrt.Equal("First", b.Title)
+	asrt.Equal([]string{"One"}, b.Bodies)
+
+	var c post
+	d = NewDecoder(strings.NewReader(multiRootPage))
+	d.RootSelector = ".post"
+	d.RootAll = true
+	e := checkErr(asrt, d.Decode(&c))
+	asrt.Equal(multipleRoots, e.Reason)
+	asrt.Equal("", c.Title)
+
+	var n post
+	d = NewDecoder(strings.NewReader(multiRootPage))
+	d.RootSelector = "main"
+	d.RootAll = true
+	asrt.NoError(d.Decode(&n))
+	asrt.Equal("", n.Title)
+}
+
+const scriptPage = `<html><head>
+<script type="application/ld+json">{"name": "Widget"}</script>
+<style>.price { color: red }</style>
+</head><body>
+<span class="price" data-amount="10">$10</span>
+</body></html>`
+
+func TestWarnOnScriptStyleMatch(t *testing.T) {
+	asrt := assert.New(t)
+
+	type page struct {
+		Items []string `goquery:"head > *"`
+		Price string   `goquery:".price"`
+	}
+
+	var a page
+	asrt.NoError(NewDecoder(strings.NewReader(scriptPage)).Decode(&a))
+	asrt.Equal(`{"name": "Widget"}`, a.Items[0])
+
+	var b page
+	d := NewDecoder(strings.NewReader(scriptPage))
+	d.WarnOnScriptStyleMatch = true
+	e := checkErr(asrt, d.Decode(&b))
+	asrt.Equal(scriptStyleMatch, e.unwind().last().Reason)
+
+	var excl struct {
+		Body string `goquery:"script,excludetext:b"`
+	}
+	d = NewDecoder(strings.NewReader(scriptPage))
+	d.WarnOnScriptStyleMatch = true
+	e = checkErr(asrt, d.Decode(&excl))
+	asrt.Equal(scriptStyleMatch, e.unwind().last().Reason)
+
+	var c struct {
+		Name   string   `goquery:"script[type='application/ld+json'],jsonpath:$.name"`
+		Types  []string `goquery:"script,[type]"`
+		Amount string   `goquery:"body *,[data-amount]"`
+		Price  string   `goquery:".price"`
+	}
+	d = NewDecoder(strings.NewReader(scriptPage))
+	d.WarnOnScriptStyleMatch = true
+	asrt.NoError(d.Decode(&c))
+	asrt.Equal("Widget", c.Name)
+	asrt.Equal([]string{"application/ld+json"}, c.Types)
+	asrt.Equal("$10", c.Price)
+}
+
+const responsivePage = `<html><body>
+<div class="mobile" style="display:none"><span class="price">$9</span>
+<time datetime="2020-01-02">Jan 2</time><a href="/m/item">Item</a></div>
+<div class="desktop"><span class="price">$10</span>
+<time datetime="2021-03-04">Mar 4</time><a href="/item">Item</a></div>
+<span class="badge" hidden>Old</span><span class="badge" aria-hidden="true">Older</span>
+</body></html>`
+
+func TestPreferVisible(t *testing.T) {
+	asrt := assert.New(t)
+
+	type product struct {
+		Price  string    `goquery:".price"`
+		Prices []string  `goquery:".price"`
+		Badge  string    `goquery:".badge"`
+		Posted time.Time `goquery:"time,[datetime]"`
+		Link   url.URL   `goquery:"a,[href]"`
+	}
+
+	var a product
+	asrt.NoError(NewDecoder(strings.NewReader(responsivePage)).Decode(&a))
+	asrt.Equal("$9$10", a.Price)
+	asrt.Equal(2020, a.Posted.Year())
+	asrt.Equal("/m/item", a.Link.String())
+
+	var b product
+	d := NewDecoder(strings.NewReader(responsivePage))
+	d.PreferVisible = true
+	asrt.NoError(d.Decode(&b))
+	asrt.Equal("$10", b.Price)
+	asrt.Equal([]string{"$9", "$10"}, b.Prices)
+	asrt.Equal("OldOlder", b.Badge)
+	asrt.Equal(2021, b.Posted.Year())
+	asrt.Equal("/item", b.Link.String())
+}
+
+const duplicatePage = `<html><body>
+<img src="a.png"><img src="b.png">
+<span class="t">go</span><span class="t">css</span>
+</body></html>`
+
+func TestPreferVisibleWholeSelection(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		NoAlt  int    `goquery:"img,countattr:alt,invert"`
+		Unique bool   `goquery:"img,isunique"`
+		Joined string `goquery:".t,join:,"`
+		Words  int    `goquery:".t,wordcount"`
+		Text   string `goquery:".t"`
+	}
+	d := NewDecoder(strings.NewReader(duplicatePage))
+	d.PreferVisible = true
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal(2, a.NoAlt)
+	asrt.False(a.Unique)
+	asrt.Equal("go,css", a.Joined)
+	asrt.Equal(1, a.Words)
+	asrt.Equal("gocss", a.Text)
+
+	var b struct {
+		Count  int    `goquery:".price,countattr:class"`
+		Joined string `goquery:".price,join:|"`
+	}
+	d = NewDecoder(strings.NewReader(responsivePage))
+	d.PreferVisible = true
+	asrt.NoError(d.Decode(&b))
+	asrt.Equal(2, b.Count)
+	asrt.Equal("$9|$10", b.Joined)
+}
@@ -16,10 +16,400 @@
 // form of an element selector followed by arbitrary comma-separated "value
 // selectors."
 //
+// - Element selectors are always evaluated within the element being
+// unmarshaled, so nested slices of structs only see their own descendants. An
+// empty element selector, as in `goquery:",[href]"`, refers to that element
+// itself. Matching still happens against the whole document, so structural
+// pseudo-classes such as `:nth-child(odd)` count siblings as they are in the
+// page rather than among the matches.
+//
+// - The fields of an untagged embedded struct are unmarshaled from the same
+// element as if they were declared in the outer struct, so a family of page
+// types can share a base struct of selectors. As with Go's promoted fields, a
+// field of the outer struct shadows a field of the same name in an embedded
+// one, and only the outer field is unmarshaled. Tagged embedded structs are
+// unmarshaled like any other field.
+//
 // - A value selector may be one of `html`, `text`, or `[someAttrName]`. `html`
 // and `text` will result in the methods of the same name being called on the
 // `*goquery.Selection` to obtain the value. `[someAttrName]` will result in
 // `*goquery.Selection.Attr("someAttrName")` being called for the value.
+// Attribute names may contain colons and other punctuation used by frontend
+// frameworks, as in `[v-bind:href]`, `[:class]` or `[@click]`. In an element
+// selector the colon must be escaped as CSS requires, which in a struct tag is
+// written `[v-bind\\:href]`.
+//
+// - The `role` value selector gives the ARIA role of the element: the first
+// role in its role attribute, or else the implicit role of the element, such
+// as "navigation" for `<nav>` or "link" for `<a href>`.
+//
+// - The `accdesc` value selector gives the accessible description of the
+// element: the text of the elements named by its aria-describedby attribute,
+// joined by spaces in the order given, or else its title attribute.
+//
+// - The `label` value selector gives the text of the label of a form control,
+// found either by a `label` element whose for attribute names the control's
+// id, or by a `label` element wrapping the control.
+//
+// - The `tristate` modifier reads a checkbox into a *bool field that is true if
+// it is checked, false if it is not, and nil if it has the indeterminate
+// attribute or aria-checked="mixed", or if nothing was matched.
+//
+// - The `checkedvalues` value selector keeps only the checked inputs among the
+// matched checkboxes or radio buttons and reads their values, so a []string
+// field holds the values a form would submit. It is empty if none are checked.
+//
+// - The `checkstate` modifier builds a map such as `map[string]bool` from the
+// matched checkboxes or radio buttons to whether each is checked, keyed by the
+// text of its label. With `checkstate:value` inputs are keyed by their value
+// attribute instead. Either way an input without that key falls back to its
+// value and then its name attribute, and is skipped if it has neither.
+//
+// - The `id` value selector is shorthand for `[id]`.
+//
+// - The `attreq:<attribute>=<value>` value selector gives "true" if the
+// element has the attribute with exactly that value, as in
+// `goquery:"img,attreq:loading=lazy"`, and "false" if the value differs or the
+// attribute is missing.
+//
+// - The `flag:<attribute>` value selector reads an attribute as a bool for
+// patterns like `data-active="1"`. Values listed in Decoder.TrueFlagValues,
+// by default "true", "1", "yes", "on" and an empty value, are true and any
+// other value is false. A missing attribute is false.
+//
+// - The `selfattr:<name>` value selector is another spelling of `[name]`,
+// meant for reading the attributes of the current element with an empty
+// element selector, as in `goquery:",selfattr:data-x"`.
+//
+// - Adding the `fallbacktext` modifier after a value selector uses the text of
+// the element when that value is empty, e.g. `goquery:"img,[alt],fallbacktext"`.
+//
+// - The `fallback:<attributes>` modifier gives a chain of attributes to try in
+// order while the value is empty, e.g. `goquery:"button,fallback:title,aria-label"`
+// reads the text, then the title and then the aria-label attribute. Each value
+// tried has its whitespace collapsed. As the list contains commas, `fallback:`
+// must be the last modifier in the tag.
+//
+// - The `selected` value selector reads the value of the selected option of a
+// `<select>` element, or the first option if none is marked as selected. An
+// option without a value attribute uses its text.
+//
+// - The `dimension:<attribute>` value selector reads a size attribute such as
+// `dimension:width`, accepting plain numbers or a "px" suffix. A missing
+// attribute gives 0.
+//
+// - The `ratio` value selector gives how far a `<progress>` or `<meter>`
+// element is between its min and max attributes, from 0 to 1. As in HTML, min
+// defaults to 0, a missing or invalid max to 1, and a missing value to 0.
+//
+// - The `clamp` value selector reads the value attribute of an element such as
+// `<meter>` or `<input type="range">`, limited to its min and max attributes.
+// A missing bound leaves that side unlimited and a missing value counts as 0.
+//
+// - The `stars` value selector reads a star rating as a number. Filled star
+// icons within the element count one each and half stars count a half, as
+// selected by Decoder.StarConfig, by default `.filled` and `.half`. Without
+// any icons, a width percentage in the inline style of the element or a
+// descendant, as in `<span style="width: 80%">`, is scaled to 5 stars, or to
+// the number of icons matched by StarConfig.Total, giving 4.
+//
+// - The `positionfraction` value selector gives the position of an element
+// among the element children of its parent as a number from 0 for the first to
+// 1 for the last. It is 0 for an only child or when nothing was matched.
+//
+// - The `attrsjson` value selector serializes the attributes of the first
+// matched element as a JSON object with sorted keys.
+//
+// - The `hasinlinehandler` value selector gives "true" if the first matched
+// element has an inline event handler, that is an attribute whose name starts
+// with "on" such as onclick, and "false" otherwise.
+//
+// - The `dir` value selector gives the text direction of the element from the
+// dir attribute of it or its nearest ancestor with one, or "ltr" if none do.
+//
+// - The `isrtl` value selector gives "true" if most of the letters in the text
+// of the element, rather than its attributes, belong to right-to-left scripts
+// such as Arabic and Hebrew, and "false" otherwise, including for text without
+// letters. Digits, punctuation and spaces are not counted.
+//
+// - The `wordcount` and `charcount` value selectors give the number of words
+// or characters in the text of the element. Characters are counted after
+// trimming and collapsing runs of whitespace to a single space.
+//
+// - The `excludetext:<selector>` value selector gives the text of the element
+// without its descendants matching the selector, so that
+// `goquery:".weight,excludetext:small"` reads "12" from
+// `<span class="weight">12<small>kg</small></span>`. The document is not
+// modified.
+//
+// - The `contents` value selector concatenates the text of the child nodes of
+// the matched elements without trimming it, so the whitespace around and
+// between them is kept where `text` would trim it.
+//
+// - The `preserve` value selector reads text with the line breaks it would
+// have when rendered, for code blocks and logs. Whitespace inside `<pre>` and
+// `<textarea>` is kept as is and collapsed elsewhere, while `<br>` and the
+// start and end of block elements such as `<p>`, `<div>` and `<li>` begin a
+// new line. Leading and trailing blank lines are removed.
+//
+// - The `isunique` value selector gives "true" if the element selector matched
+// exactly one element, and "false" if it matched none or several. Used with a
+// bool field it flags a selector that has become ambiguous without failing.
+//
+// - The `iserrorpage` value selector gives "true" if the whole document looks
+// like an error page, whichever element the field matched. See IsErrorPage.
+//
+// - The `charset` value selector gives the character encoding the whole
+// document declares in a `<meta charset>` or http-equiv Content-Type meta
+// tag, in lower case, such as "windows-1252". It is empty if none is declared.
+//
+// - The `countattr:<attribute>` value selector gives the number of matched
+// elements having the attribute, whatever its value. With the `invert`
+// modifier it counts those lacking it instead, as in
+// `goquery:"img,countattr:alt,invert"` for images without alt text.
+//
+// - The `countfind:<selector>` value selector gives the number of descendants
+// of the element matching the selector, such as `countfind:.reply`. As the tag
+// is split on commas, the selector cannot contain one.
+//
+// - The `classes` modifier fills a []string with the class names of the
+// matched elements, and `classes:<prefix>` keeps only those starting with the
+// prefix, such as `classes:state-`.
+//
+// - The `breadcrumbs` modifier fills a slice of strings with the trail of a
+// breadcrumb container such as `goquery:"nav.breadcrumb,breadcrumbs"`: the
+// text of each link in order, then the current page if it is marked with
+// aria-current and is not a link. The trail in the markup takes precedence,
+// and only if it is empty, or the container is missing, are the items of the
+// first JSON-LD BreadcrumbList of the document used, ordered by position.
+//
+// - The `links` modifier fills a slice of url.URL, *url.URL or string with the
+// hrefs of every link within the matched elements, resolved against
+// Decoder.BaseURL, as in `goquery:"nav,links"`. Adding the `unique` modifier
+// skips links already seen.
+//
+// - The `groupby:<selector>` modifier splits the matched elements of a slice
+// of slices, such as [][]Item, into runs of consecutive elements. A new run
+// starts at each element matching the separator selector in document order.
+// Leading, trailing or repeated separators never produce empty runs.
+//
+// - With the `nilempty` modifier, a slice of pointers holds nil for each
+// matched element whose value, as read by the value selector, is empty, rather
+// than a pointer to a zero value.
+//
+// - A url.URL value is parsed from the value and resolved against
+// Decoder.BaseURL if it is set.
+//
+// - The `stripquery:<patterns>` modifier removes the query parameters of a
+// url.URL value whose names match any of the glob patterns, separated by ";",
+// such as `goquery:"a.product,[href],stripquery:utm_*;fbclid"` to drop tracking
+// parameters. The order of the remaining parameters is kept.
+//
+// - The `picturesrc` value selector reads the URL of the image shown by a
+// `<picture>` element, resolved against Decoder.BaseURL. The src of its
+// fallback `<img>` is preferred, and failing that the first candidate in the
+// srcset of its first `<source>` that has one.
+//
+// - The `tel` and `email` modifiers read a phone number or email address,
+// preferring the href of a `tel:` or `mailto:` link without its scheme and
+// parameters, and otherwise using the value, such as the text. Phone numbers
+// are stripped of spaces, hyphens, dots, slashes and parentheses. A value that
+// does not look like a phone number or email address leaves the field empty,
+// or is an error if Decoder.StrictContacts is set.
+//
+// - The `bgimage` value selector reads the URL of the background image set by
+// the inline style of the element, resolved against Decoder.BaseURL.
+//
+// - The `style:<property>` value selector reads a property from the inline
+// style attribute of the element, and `cssvar:<name>` reads a custom property
+// such as `--progress`.
+//
+// - The `parent` modifier moves from the matched elements to their parents
+// before the value is read, and `parentsup:N` climbs N levels. Climbing past
+// the root element leaves nothing matched.
+//
+// - The `regexp:<expr>` modifier matches a regular expression against the
+// value, keeping the first capture group or else the whole match. On a struct
+// field, named capture groups set the struct fields of the same name, ignoring
+// case, and fields without a matching group keep their zero value. As
+// expressions may contain commas, `regexp:` must be the last modifier in the
+// tag.
+//
+// - The `datastruct:<prefix>` modifier fills a struct field from the
+// `data-<prefix>-*` attributes of the matched element, so `data-user-name` and
+// `data-user-age` set the Name and Age fields with `goquery:".card,datastruct:user"`.
+// The rest of each attribute name is matched to a field name without its
+// hyphens and ignoring case, so `data-user-first-name` sets FirstName.
+// Fields without a matching attribute keep their zero value.
+//
+// - The `where:<name>` modifier keeps only the matched elements accepted by the
+// predicate registered under that name with Decoder.RegisterFilter. Naming a
+// filter that was not registered is an error.
+//
+// - The `dedupeby:<value selector>` modifier drops matched elements whose key,
+// read with a value selector such as `dedupeby:[data-id]`, was already seen.
+// Elements with an empty key are always kept.
+//
+// - The `ancestor:<selector>` modifier moves to the closest ancestor of each
+// matched element that matches the selector, or with `nth:N` the Nth closest.
+// Elements without enough matching ancestors are dropped.
+//
+// - The `pick:longest` and `pick:shortest` modifiers keep only the matched
+// element with the most or the fewest characters of text, so that the real
+// content can be told apart from boilerplate matching the same selector.
+// `pick:mode` keeps the first element with the text that occurs most often.
+// Ties go to the first in document order.
+//
+// - The `tmpl:<name>` modifier renders a template registered with
+// Decoder.RegisterTemplate into the field. The template receives the matched
+// *goquery.Selection, so `{{(.Find ".first").Text}}` reads a child's text.
+//
+// - A numeric field may be computed over every matched element with the
+// `agg:sum`, `agg:avg`, `agg:min`, `agg:max` or `agg:count` modifiers. The
+// value of each element is read with the value selector, such as
+// `goquery:".item,[data-weight],agg:sum"`, rewritten by any `jsonpath:`,
+// `replace:` or `regexp:` modifiers, and parsed as a number. Elements
+// that are not numeric are an error unless the `skipinvalid` modifier is also
+// given. `agg:count` is the number of matched elements, whatever their values.
+//
+// - A time.Time field may be set to the earliest or latest of the times of the
+// matched elements with `agg:mindate` or `agg:maxdate`, as in
+// `goquery:".event time,[datetime],agg:mindate"`. Each value is parsed as for
+// any time.Time, so a `layout:` modifier may follow. Empty values are skipped,
+// other invalid times are an error unless `skipinvalid` is given, and the field
+// is left as it is if nothing was found.
+//
+// - The `join:<separator>` modifier fills a string field with the values of
+// all the matched elements joined by the separator, skipping empty ones. Options
+// may follow the separator after semicolons or be given as modifiers before
+// it: `unique` drops repeated values, `sort` sorts them lexically and `isort`
+// sorts them ignoring case. For example `goquery:".tag,join:,;unique;sort"`
+// gives "a,b,c". As the separator may be a comma, `join:` must be the last
+// modifier.
+//
+// - The `csv` modifier parses the value of the element as CSV into a slice of
+// structs, one for each record after the header. Columns are matched to
+// fields by name regardless of case, spaces, hyphens and underscores, so a
+// "Unit Price" column sets UnitPrice, and unmatched columns are ignored.
+// Malformed CSV is an error.
+//
+// - The `jsonpath:<path>` modifier reads one value out of JSON held in the
+// element, such as a JSON-LD script or a data attribute, before it is
+// converted. The path may be dotted with array indices, as in
+// `goquery:"script[type='application/ld+json'],jsonpath:$.offers[0].price"`,
+// or an RFC 6901 JSON Pointer like `/offers/0/price`. Objects and arrays are
+// given as JSON, and a path that is not present gives an empty value.
+//
+// - The `replace:` modifier applies literal replacements to the value before
+// it is converted, in the order given. Each is written `old=>new`, and they
+// are separated by ";", as in `goquery:".rating,replace:Rating:=>;,=>."`,
+// which turns "Rating: 4,5" into a float64 of 4.5 as the result is trimmed. A
+// backslash makes the next character literal, so `\;` is a semicolon and `\=>`
+// is not a separator; in a struct tag the backslash itself is written twice. As
+// the replacements may contain commas, `replace:` must be the last modifier.
+//
+// - The modifiers rewriting a value, `jsonpath:`, `replace:`, `regexp:`, `slug`
+// and `truncate:`, also apply to each element read by `agg:`, `distinctcount`
+// and `relativetime`, so `goquery:".price,agg:sum,replace:$=>"` totals prices
+// written as "$1.50". They do not combine with `rowtotal`, `readingtime`,
+// `tel`, `email` or `tmpl:`, which read the document themselves.
+//
+// - The `slug` modifier turns the value into a URL slug for building anchors,
+// so `goquery:"h2,slug"` reads "Hello, World!" as "hello-world". Letters are
+// lower-cased and letters and digits of any script are kept. Apostrophes are
+// dropped, and any other run of characters becomes a single hyphen, with no
+// hyphen at the start or end. The slug is made after `replace:` and `regexp:`.
+//
+// - The `truncate:<n>` modifier shortens the value for previews, such as
+// `goquery:".summary,truncate:120"`. Whitespace is collapsed first, and a value
+// longer than n characters is cut to its first n, counted as runes so that
+// multibyte characters are never split, followed by "…". Shorter values are
+// only collapsed. Truncation is the last step, after `slug`.
+//
+// - The `distinctcount` modifier sets a numeric field to the number of
+// distinct values among the matched elements, as read by the value selector.
+// Values are compared after collapsing whitespace, and empty values are not
+// counted.
+//
+// - The `nextmatching:<selector>` modifier moves from each matched element to
+// its first following sibling matching the selector, skipping any others in
+// between, as in `goquery:"dt.price,nextmatching:dd"`. An element with no such
+// sibling is dropped, leaving the field empty if none had one.
+//
+// - The `rdfa:<property>` modifier reads an RDFa property of the current
+// element, usually with an empty element selector as in
+// `goquery:",rdfa:name"`. Properties are found among the descendants that
+// belong to the current item rather than to a nested `typeof` item, and may be
+// given with or without a vocabulary prefix. The value is taken from the
+// content, resource, href, src or datetime attribute, in that order, or else
+// the text.
+//
+// - The `dl` modifier builds a map from definition lists keyed by the text of
+// each `dt`. A `map[string][]string` collects every `dd` following a `dt` up
+// to the next `dt`, while other value types use the first. Consecutive `dt`s
+// share the `dd`s that follow them, and a `dd` with no preceding `dt` is
+// ignored.
+//
+// - The `grid` modifier builds a map from a two column table, with the first
+// cell of each row as the key and the second as the value. It may be given the
+// table or its rows, and rows with fewer than two cells are skipped.
+//
+// - The `headers` modifier fills a slice of maps, such as
+// `[]map[string]string`, with one map for each body row of the matched
+// tables, keyed by the header of each column. The header rows are those in the
+// `thead`, or else the leading rows made only of `th` cells. With several
+// header rows, the headers above a column are joined with " / " into keys
+// like "Revenue / Q1", honouring colspan and rowspan, and `headersep:<sep>`
+// sets another separator. A body cell spanning several rows, such as a region
+// grouping them, is repeated in each row it covers.
+//
+// - The `rowtotal` modifier sets a numeric field to the sum of the `td` cells
+// of the matched table row, usually with an empty element selector in a struct
+// decoded per row, as in `goquery:",rowtotal"`. Thousands separators are
+// ignored and cells that are not numeric, such as labels, are skipped. The
+// `cells:<selector>` modifier picks other cells to sum, such as `td.amount`.
+//
+// - The `table` modifier fills a slice of slices, such as `[][]string`, with
+// one slice of cell texts for each row of the matched tables, without mapping
+// any headers. Rows in `thead`, `tbody` and `tfoot` are treated alike, header
+// and data cells are both included, and a cell spanning several columns or
+// rows is repeated in each of them.
+//
+// - The `thtd` modifier builds a map from table rows of the form
+// `<tr><th>Key</th><td>Value</td></tr>`, keyed by the text of the `th`. A
+// `map[string][]string` collects every `td` of a row while other value types
+// use the first. Rows without a `th` or a `td` are skipped.
+//
+// - The `indexmap` modifier builds a map such as `map[int]string` from the
+// text of the matched elements, keyed by the position of each among all the
+// elements the selector matched, counted from 0. Keys are therefore kept when
+// `where:`, `dedupeby:` or `pick:` drop some matches, while a selector such as
+// `li:nth-child(odd)` is part of the match and numbers its elements 0, 1, 2.
+//
+// - The `groupcount:<value selector>` modifier builds a map such as
+// `map[string]int` counting the matched elements per distinct value, e.g.
+// `goquery:".item,groupcount:[data-category]"`. Elements whose value is empty
+// or missing are counted under the key given by `emptykey:`, by default "".
+// A bare `groupcount:` groups by text.
+//
+// - The `kv` modifier builds a map from repeated rows that each hold a key and
+// a value element, e.g. `goquery:".row,kv,key:.k,value:.v"`. Rows without a
+// key are skipped and later rows replace earlier rows with the same key.
+//
+// - A time.Time value is parsed with the layout given by a `layout:` modifier,
+// or else with the first matching common layout such as RFC 3339. Times without
+// a zone are interpreted in Decoder.DefaultLocation. As layouts may contain
+// commas, `layout:` must be the last modifier in the tag.
+//
+// - The `relativetime` modifier parses phrases such as "3 days ago", "2h ago",
+// "an hour ago", "in 2 weeks" or "yesterday" into a time.Time counted back
+// from Decoder.Now, or into a time.Duration of how long ago it was. Units from
+// seconds to years are understood, with a month taken as 30 days and a year as
+// 365. Other phrases are an error.
+//
+// - The `readingtime` modifier sets a time.Duration field to an estimate of how
+// long the text of the element takes to read, from its word count and
+// Decoder.WordsPerMinute, by default 200, rounded to the second.
 //
 // - A primitive value type will default to the text value of the resulting
 // nodes if no value selector is given.
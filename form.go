@@ -0,0 +1,143 @@
+package goq
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// rootSelection selects the root of the tree holding the first element of s.
+func rootSelection(s *goquery.Selection) *goquery.Selection {
+	if len(s.Nodes) == 0 {
+		return s
+	}
+	n := s.Nodes[0]
+	for n.Parent != nil {
+		n = n.Parent
+	}
+	return NodeSelector([]*html.Node{n})
+}
+
+// labelFor finds the label of a form control: a label whose for attribute
+// names the control's id, or else a label wrapping the control.
+func labelFor(s *goquery.Selection) *goquery.Selection {
+	if id, ok := s.Attr("id"); ok && id != "" {
+		label := rootSelection(s).Find("label[for]").FilterFunction(func(_ int, l *goquery.Selection) bool {
+			return l.AttrOr("for", "") == id
+		})
+		if label.Length() > 0 {
+			return label.First()
+		}
+	}
+	return s.First().Closest("label")
+}
+
+// labelVal returns the text of the label of a form control, leaving out the
+// text of any controls the label wraps, such as the options of a select.
+func labelVal(d *Decoder, s *goquery.Selection) string {
+	label := labelFor(s)
+	if label.Length() == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			buf.WriteString(n.Data)
+		case n.Type == html.ElementNode && (n.Data == "select" || n.Data == "textarea" || n.Data == "button"):
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(label.Nodes[0])
+	return collapseSpace(buf.String())
+}
+
+// unmarshalTristate sets the *bool v to the state of the first checkbox in the
+// selection: true if checked, false if unchecked, and nil if it is
+// indeterminate or nothing was matched.
+func unmarshalTristate(s *goquery.Selection, v reflect.Value) error {
+	if v.Type() != reflect.TypeOf((*bool)(nil)) {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    fmt.Errorf("tristate needs a *bool, not %s", v.Type()),
+		}
+	}
+
+	box := s.First()
+	_, indeterminate := box.Attr("indeterminate")
+	if box.Length() == 0 || indeterminate || box.AttrOr("aria-checked", "") == "mixed" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	_, checked := box.Attr("checked")
+	v.Set(reflect.ValueOf(&checked))
+	return nil
+}
+
+// unmarshalCheckState builds a map from each checkbox or radio button in the
+// selection to whether it is checked. Inputs are keyed by the text of their
+// label, or by their value attribute if keyBy is "value", falling back to the
+// value and then the name attribute when the preferred key is empty.
+func (d *Decoder) unmarshalCheckState(s *goquery.Selection, v reflect.Value, keyBy string) error {
+	switch keyBy {
+	case "", "label", "value":
+	default:
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    fmt.Errorf("unknown checkstate key %q", keyBy),
+		}
+	}
+
+	keyT, eleT := v.Type().Key(), v.Type().Elem()
+	for i := range s.Nodes {
+		in := s.Eq(i)
+		key := ""
+		if keyBy != "value" {
+			key = labelVal(d, in)
+		}
+		if key == "" {
+			key = in.AttrOr("value", "")
+		}
+		if key == "" {
+			key = in.AttrOr("name", "")
+		}
+		if key == "" {
+			continue
+		}
+
+		newK, newV := reflect.New(keyT).Elem(), reflect.New(eleT).Elem()
+		if err := unmarshalLiteral(key, newK); err != nil {
+			return &CannotUnmarshalError{
+				Reason:   mapKeyUnmarshalError,
+				V:        v,
+				Err:      err,
+				FldOrIdx: key,
+				Val:      key,
+			}
+		}
+		checked := strconv.FormatBool(hasAttr(in, "checked"))
+		if err := unmarshalLiteral(checked, newV); err != nil {
+			return &CannotUnmarshalError{
+				Reason:   typeConversionError,
+				V:        v,
+				Err:      err,
+				FldOrIdx: key,
+				Val:      checked,
+			}
+		}
+		v.SetMapIndex(newK, newV)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const labelPage = `<html><body>
+<form>
+	<label for="email">Email  address</label>
+	<input id="email" name="email">
+	<label>
+		Country
+		<select name="country"><option>Australia</option></select>
+	</label>
+	<label><input type="checkbox" name="terms"> I agree</label>
+	<input name="orphan">
+</form>
+</body></html>`
+
+func TestLabel(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Email   string `goquery:"input[name=email],label"`
+		Country string `goquery:"select[name=country],label"`
+		Terms   string `goquery:"input[name=terms],label"`
+		Orphan  string `goquery:"input[name=orphan],label"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(labelPage), &a))
+	asrt.Equal("Email address", a.Email)
+	asrt.Equal("Country", a.Country)
+	asrt.Equal("I agree", a.Terms)
+	asrt.Equal("", a.Orphan)
+}
+
+const tristatePage = `<html><body>
+<input type="checkbox" id="all" indeterminate checked>
+<input type="checkbox" id="mixed" aria-checked="mixed">
+<input type="checkbox" id="on" checked>
+<input type="checkbox" id="off">
+</body></html>`
+
+func TestTristate(t *testing.T) {
+	asrt := assert.New(t)
+
+	yes := true
+	var a struct {
+		All     *bool   `goquery:"#all,tristate"`
+		Mixed   *bool   `goquery:"#mixed,tristate"`
+		On      *bool   `goquery:"#on,tristate"`
+		Off     *bool   `goquery:"#off,tristate"`
+		Missing *bool   `goquery:"#missing,tristate"`
+		Boxes   []*bool `goquery:"input,tristate"`
+	}
+	a.All = &yes
+
+	asrt.NoError(Unmarshal([]byte(tristatePage), &a))
+	asrt.Nil(a.All)
+	asrt.Nil(a.Mixed)
+	if asrt.NotNil(a.On) {
+		asrt.True(*a.On)
+	}
+	if asrt.NotNil(a.Off) {
+		asrt.False(*a.Off)
+	}
+	asrt.Nil(a.Missing)
+	asrt.Len(a.Boxes, 4)
+	asrt.Nil(a.Boxes[0])
+	asrt.True(*a.Boxes[2])
+
+	var b struct {
+		On bool `goquery:"#on,tristate"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(tristatePage), &b))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}
+
+const checkStatePage = `<html><body>
+<fieldset>
+	<label><input type="checkbox" name="topping" value="ham" checked> Ham</label>
+	<label><input type="checkbox" name="topping" value="olives"> Olives</label>
+	<input type="checkbox" id="cheese" name="topping" value="cheese" checked>
+	<label for="cheese">Extra  cheese</label>
+	<input type="checkbox" name="topping" value="basil">
+</fieldset>
+</body></html>`
+
+func TestCheckState(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		ByLabel map[string]bool `goquery:"input,checkstate"`
+		ByValue map[string]bool `goquery:"input,checkstate:value"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(checkStatePage), &a))
+	asrt.Equal(map[string]bool{
+		"Ham":          true,
+		"Olives":       false,
+		"Extra cheese": true,
+		"basil":        false,
+	}, a.ByLabel)
+	asrt.Equal(map[string]bool{
+		"ham":    true,
+		"olives": false,
+		"cheese": true,
+		"basil":  false,
+	}, a.ByValue)
+
+	var b struct {
+		State map[string]bool `goquery:"input,checkstate:id"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(checkStatePage), &b))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}
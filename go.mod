@@ -4,12 +4,12 @@ go 1.22
 
 require (
 	github.com/PuerkitoBio/goquery v1.9.2
+	github.com/andybalholm/cascadia v1.3.2
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/net v0.26.0
 )
 
 require (
-	github.com/andybalholm/cascadia v1.3.2 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
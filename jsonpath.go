@@ -0,0 +1,85 @@
+package goq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegments splits a path into its keys and indices. A path starting
+// with "/" is an RFC 6901 JSON Pointer. Any other is a dotted path such as
+// `$.offers[0].price`, where the leading "$" is optional.
+func jsonPathSegments(path string) []string {
+	if strings.HasPrefix(path, "/") {
+		segs := strings.Split(path[1:], "/")
+		for i, seg := range segs {
+			segs[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(seg)
+		}
+		return segs
+	}
+
+	path = strings.TrimPrefix(path, "$")
+	var segs []string
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			open := strings.IndexByte(part, '[')
+			if open < 0 {
+				segs = append(segs, part)
+				break
+			}
+			if open > 0 {
+				segs = append(segs, part[:open])
+			}
+			end := strings.IndexByte(part[open:], ']')
+			if end < 0 {
+				segs = append(segs, part[open+1:])
+				break
+			}
+			segs = append(segs, part[open+1:open+end])
+			part = part[open+end+1:]
+		}
+	}
+	return segs
+}
+
+// jsonPathVal looks up path in the JSON document str. Strings are given as is,
+// numbers and booleans as written, null as an empty string, and objects and
+// arrays as JSON. A path that is not present gives an empty string.
+func jsonPathVal(str, path string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(str))
+	dec.UseNumber()
+	var cur interface{}
+	if err := dec.Decode(&cur); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	for _, seg := range jsonPathSegments(path) {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			cur = node[seg]
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", nil
+			}
+			cur = node[i]
+		default:
+			return "", nil
+		}
+	}
+
+	switch val := cur.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	default:
+		bs, err := json.Marshal(val)
+		return string(bs), err
+	}
+}
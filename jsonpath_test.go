@@ -0,0 +1,58 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const jsonLDPage = `<html><head>
+<script type="application/ld+json">
+{
+	"@type": "Product",
+	"name": "Anvil",
+	"offers": [
+		{"price": 19.99, "priceCurrency": "USD", "inStock": true},
+		{"price": 17.5, "priceCurrency": "EUR", "inStock": false}
+	],
+	"brand": {"name": "ACME", "a/b": "slash"},
+	"gtin": null
+}
+</script>
+</head><body>
+<div id="cfg" data-config='{"page": {"size": 25}}'></div>
+<div id="broken" data-config='{"page":'></div>
+</body></html>`
+
+func TestJSONPath(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Price    float64 `goquery:"script[type='application/ld+json'],jsonpath:$.offers[0].price"`
+		Currency string  `goquery:"script[type='application/ld+json'],jsonpath:offers[1].priceCurrency"`
+		InStock  bool    `goquery:"script[type='application/ld+json'],jsonpath:/offers/0/inStock"`
+		Brand    string  `goquery:"script[type='application/ld+json'],jsonpath:$.brand.name"`
+		Slash    string  `goquery:"script[type='application/ld+json'],jsonpath:/brand/a~1b"`
+		Offer    string  `goquery:"script[type='application/ld+json'],jsonpath:$.offers[1]"`
+		GTIN     string  `goquery:"script[type='application/ld+json'],jsonpath:$.gtin"`
+		Missing  string  `goquery:"script[type='application/ld+json'],jsonpath:$.offers[5].price"`
+		PageSize int     `goquery:"#cfg,[data-config],jsonpath:page.size"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(jsonLDPage), &a))
+	asrt.Equal(19.99, a.Price)
+	asrt.Equal("EUR", a.Currency)
+	asrt.True(a.InStock)
+	asrt.Equal("ACME", a.Brand)
+	asrt.Equal("slash", a.Slash)
+	asrt.JSONEq(`{"price": 17.5, "priceCurrency": "EUR", "inStock": false}`, a.Offer)
+	asrt.Equal("", a.GTIN)
+	asrt.Equal("", a.Missing)
+	asrt.Equal(25, a.PageSize)
+
+	var b struct {
+		Broken string `goquery:"#broken,[data-config],jsonpath:page"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(jsonLDPage), &b))
+	asrt.Equal(typeConversionError, e.unwind().last().Reason)
+}
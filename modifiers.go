@@ -0,0 +1,887 @@
+package goq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// collapseSpace trims s and replaces each run of whitespace with one space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// wordCountVal counts the whitespace-separated words in the selection text.
+func wordCountVal(d *Decoder, s *goquery.Selection) string {
+	return strconv.Itoa(len(strings.Fields(d.text(s))))
+}
+
+// charCountVal counts the characters in the selection text once whitespace
+// has been collapsed.
+func charCountVal(d *Decoder, s *goquery.Selection) string {
+	return strconv.Itoa(utf8.RuneCountInString(collapseSpace(d.text(s))))
+}
+
+// excludeTextVal gives the text of a copy of the selection with the
+// descendants matching sel removed, leaving the document itself untouched.
+func excludeTextVal(sel string) valFunc {
+	return func(d *Decoder, s *goquery.Selection) string {
+		clone := s.Clone()
+		clone.Find(sel).Remove()
+		return strings.TrimSpace(d.text(clone))
+	}
+}
+
+// contentsVal concatenates the text of the child nodes of the selection,
+// keeping the whitespace that textVal would trim.
+func contentsVal(d *Decoder, s *goquery.Selection) string {
+	return d.text(s)
+}
+
+// blockElements are the elements whose start and end break the line in
+// preserveVal.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"dd": true, "details": true, "div": true, "dl": true, "dt": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true,
+	"form": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true,
+	"h6": true, "header": true, "hr": true, "li": true, "main": true,
+	"nav": true, "ol": true, "p": true, "pre": true, "section": true,
+	"summary": true, "table": true, "tr": true, "ul": true,
+}
+
+// preserveVal gives the text of the selection with the line structure it would
+// have when rendered. Whitespace is kept as is inside pre and textarea elements
+// and collapsed elsewhere, and br elements and the boundaries of block elements
+// start a new line.
+func preserveVal(d *Decoder, s *goquery.Selection) string {
+	var out []byte
+	newline := func() {
+		out = bytes.TrimRight(out, " ")
+		if len(out) > 0 && out[len(out)-1] != '\n' {
+			out = append(out, '\n')
+		}
+	}
+
+	var walk func(n *html.Node, pre bool)
+	walk = func(n *html.Node, pre bool) {
+		switch n.Type {
+		case html.TextNode:
+			if pre {
+				out = append(out, n.Data...)
+				return
+			}
+			for _, r := range n.Data {
+				if !unicode.IsSpace(r) {
+					out = utf8.AppendRune(out, r)
+				} else if len(out) > 0 && out[len(out)-1] != ' ' && out[len(out)-1] != '\n' {
+					out = append(out, ' ')
+				}
+			}
+			return
+		case html.ElementNode:
+			if d.ignored(n.Data) {
+				return
+			}
+			if n.Data == "br" {
+				out = bytes.TrimRight(out, " ")
+				out = append(out, '\n')
+				return
+			}
+			pre = pre || n.Data == "pre" || n.Data == "textarea"
+		}
+
+		block := n.Type == html.ElementNode && blockElements[n.Data]
+		if block {
+			newline()
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, pre)
+		}
+		if block {
+			newline()
+		}
+	}
+	for _, n := range s.Nodes {
+		pre := false
+		for p := n.Parent; p != nil && !pre; p = p.Parent {
+			pre = p.Type == html.ElementNode && (p.Data == "pre" || p.Data == "textarea")
+		}
+		walk(n, pre)
+	}
+	return strings.TrimRight(strings.TrimLeft(string(out), "\n"), " \n")
+}
+
+// isUniqueVal reports whether the selection matched exactly one element.
+func isUniqueVal(_ *Decoder, s *goquery.Selection) string {
+	return strconv.FormatBool(s.Length() == 1)
+}
+
+// countAttrVal counts the elements of the selection that have the attribute,
+// or that lack it if invert is set.
+func countAttrVal(attr string, invert bool) valFunc {
+	return func(_ *Decoder, s *goquery.Selection) string {
+		n := 0
+		for i := range s.Nodes {
+			if hasAttr(s.Eq(i), attr) != invert {
+				n++
+			}
+		}
+		return strconv.Itoa(n)
+	}
+}
+
+// countFindVal counts the descendants of the selection matching sel.
+func countFindVal(sel string) valFunc {
+	return func(_ *Decoder, s *goquery.Selection) string {
+		return strconv.Itoa(s.Find(sel).Length())
+	}
+}
+
+// DefaultTrueFlagValues are the attribute values read as true by
+// `flag:<attribute>` unless Decoder.TrueFlagValues is set. The empty string
+// lets a flag be given as a bare attribute, as in `<div data-active>`.
+var DefaultTrueFlagValues = []string{"", "true", "1", "yes", "on"}
+
+// flagVal reads an attribute of the first element as a bool, true if its value
+// is one of the decoder's true flag values. A missing attribute is false.
+func flagVal(attr string) valFunc {
+	return func(d *Decoder, s *goquery.Selection) string {
+		val, ok := s.Attr(attr)
+		if !ok {
+			return "false"
+		}
+		truthy := d.TrueFlagValues
+		if truthy == nil {
+			truthy = DefaultTrueFlagValues
+		}
+		val = strings.TrimSpace(val)
+		for _, t := range truthy {
+			if strings.EqualFold(val, t) {
+				return "true"
+			}
+		}
+		return "false"
+	}
+}
+
+// attrEqVal reports whether the first element has an attribute equal to a
+// value, given as "name=value".
+func attrEqVal(arg string) valFunc {
+	name, want, _ := strings.Cut(arg, "=")
+	return func(_ *Decoder, s *goquery.Selection) string {
+		val, ok := s.Attr(name)
+		return strconv.FormatBool(ok && val == want)
+	}
+}
+
+// checkedVal returns the value submitted for a checkbox or radio input, which
+// is "on" when it has no value attribute.
+func checkedVal(_ *Decoder, s *goquery.Selection) string {
+	if val, ok := s.Attr("value"); ok {
+		return val
+	}
+	return "on"
+}
+
+// dirVal returns the text direction inherited by the element from the nearest
+// dir attribute on it or its ancestors, defaulting to "ltr".
+func dirVal(_ *Decoder, s *goquery.Selection) string {
+	if dir, ok := s.First().Closest("[dir]").Attr("dir"); ok {
+		return strings.ToLower(strings.TrimSpace(dir))
+	}
+	return "ltr"
+}
+
+// rtlScripts are the scripts written from right to left.
+var rtlScripts = []*unicode.RangeTable{
+	unicode.Arabic, unicode.Hebrew, unicode.Nko, unicode.Syriac, unicode.Thaana,
+}
+
+// isRTLVal reports whether most of the letters in the text of the selection
+// belong to right-to-left scripts such as Arabic and Hebrew. Text without
+// letters is not right-to-left.
+func isRTLVal(d *Decoder, s *goquery.Selection) string {
+	var letters, rtl int
+	for _, r := range d.text(s) {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.In(r, rtlScripts...) {
+			rtl++
+		}
+	}
+	return strconv.FormatBool(rtl*2 > letters)
+}
+
+// attrsJSONVal serializes the attributes of the first element as a JSON object
+// with sorted keys. It is empty if nothing was matched.
+func attrsJSONVal(_ *Decoder, s *goquery.Selection) string {
+	if len(s.Nodes) == 0 {
+		return ""
+	}
+	attrs := map[string]string{}
+	for _, a := range s.Nodes[0].Attr {
+		attrs[a.Key] = a.Val
+	}
+	bs, _ := json.Marshal(attrs)
+	return string(bs)
+}
+
+// hasInlineHandlerVal reports whether the first element has an inline event
+// handler attribute such as onclick.
+func hasInlineHandlerVal(_ *Decoder, s *goquery.Selection) string {
+	if len(s.Nodes) == 0 {
+		return "false"
+	}
+	for _, a := range s.Nodes[0].Attr {
+		if len(a.Key) > 2 && strings.EqualFold(a.Key[:2], "on") {
+			return "true"
+		}
+	}
+	return "false"
+}
+
+// dimensionVal reads a size attribute such as width or height, dropping a
+// trailing "px" unit. A missing attribute gives 0.
+func dimensionVal(attr string) valFunc {
+	return func(_ *Decoder, s *goquery.Selection) string {
+		str := strings.TrimSpace(s.AttrOr(attr, ""))
+		if len(str) > 2 && strings.EqualFold(str[len(str)-2:], "px") {
+			str = strings.TrimSpace(str[:len(str)-2])
+		}
+		if str == "" {
+			return "0"
+		}
+		return str
+	}
+}
+
+// floatAttr parses a numeric attribute of the element, reporting false if it is
+// missing or not a number.
+func floatAttr(s *goquery.Selection, name string) (float64, bool) {
+	str, ok := s.Attr(name)
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	return f, err == nil
+}
+
+// ratioVal gives how far the value of a progress or meter element is between
+// its min and max attributes, as a number from 0 to 1. As in HTML, min defaults
+// to 0 and a missing or invalid max to 1, and a missing value counts as 0.
+func ratioVal(_ *Decoder, s *goquery.Selection) string {
+	if len(s.Nodes) == 0 {
+		return ""
+	}
+	min, _ := floatAttr(s, "min")
+	max, ok := floatAttr(s, "max")
+	if !ok || max <= min {
+		max = min + 1
+	}
+	val, _ := floatAttr(s, "value")
+
+	r := (val - min) / (max - min)
+	if r < 0 {
+		r = 0
+	} else if r > 1 {
+		r = 1
+	}
+	return strconv.FormatFloat(r, 'f', -1, 64)
+}
+
+// clampVal gives the value attribute of the element limited to its min and max
+// attributes. A missing bound leaves that side unlimited, and a missing value
+// counts as 0.
+func clampVal(_ *Decoder, s *goquery.Selection) string {
+	if len(s.Nodes) == 0 {
+		return ""
+	}
+	val, _ := floatAttr(s, "value")
+	if max, ok := floatAttr(s, "max"); ok && val > max {
+		val = max
+	}
+	if min, ok := floatAttr(s, "min"); ok && val < min {
+		val = min
+	}
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
+// positionFractionVal gives the position of the first element among the
+// element children of its parent as a fraction from 0 for the first to 1 for
+// the last. It is 0 for an only child or an empty selection.
+func positionFractionVal(_ *Decoder, s *goquery.Selection) string {
+	if len(s.Nodes) == 0 {
+		return "0"
+	}
+	e := s.First()
+	n := e.Siblings().Length()
+	if n == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(e.Index())/float64(n), 'f', -1, 64)
+}
+
+// slugify turns text such as a heading into a URL slug. Letters are
+// lower-cased, apostrophes are dropped and every other run of characters that
+// are not letters or digits becomes a single hyphen, with none at either end.
+func slugify(str string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(str) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		case r == '\'' || r == '’':
+		default:
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// truncate collapses the whitespace of str and cuts it to at most n
+// characters, followed by an ellipsis if anything was cut.
+func truncate(str string, n int) string {
+	str = collapseSpace(str)
+	if utf8.RuneCountInString(str) <= n {
+		return str
+	}
+	runes := []rune(str)
+	return strings.TrimRight(string(runes[:n]), " ") + "…"
+}
+
+// setNumber assigns a computed number to a numeric, string or empty interface
+// value.
+func setNumber(f float64, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(f))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(f))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(f)
+	default:
+		return unmarshalLiteral(strconv.FormatFloat(f, 'f', -1, 64), v)
+	}
+	return nil
+}
+
+// unmarshalAggregate parses the value of each element in the selection, as
+// rewritten by the modifiers of the tag, as a number and sets v to the result
+// of the named aggregate function. Elements that are not numeric are an error
+// unless the tag has the `skipinvalid` modifier. The count function counts the
+// elements without parsing them.
+func (d *Decoder) unmarshalAggregate(s *goquery.Selection, v reflect.Value, tag goqueryTag, fn string) error {
+	switch fn {
+	case "count", "sum", "avg", "min", "max":
+	case "mindate", "maxdate":
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    fmt.Errorf("agg:%s needs a time.Time, not %s", fn, v.Type()),
+		}
+	default:
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    fmt.Errorf("unknown aggregate function %q", fn),
+		}
+	}
+
+	if fn == "count" {
+		return setNumber(float64(s.Length()), v)
+	}
+
+	_, skip := tag.modifier("skipinvalid")
+	vf := tag.valFunc()
+
+	var nums []float64
+	for i := range s.Nodes {
+		str, err := d.transformValue(vf(d, s.Eq(i)), v, tag)
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			if skip {
+				continue
+			}
+			return &CannotUnmarshalError{
+				V:        v,
+				Reason:   typeConversionError,
+				Err:      err,
+				Val:      str,
+				FldOrIdx: i,
+			}
+		}
+		nums = append(nums, f)
+	}
+
+	var res float64
+	switch fn {
+	case "sum", "avg":
+		for _, n := range nums {
+			res += n
+		}
+		if fn == "avg" && len(nums) > 0 {
+			res /= float64(len(nums))
+		}
+	case "min", "max":
+		for i, n := range nums {
+			if i == 0 || (fn == "min" && n < res) || (fn == "max" && n > res) {
+				res = n
+			}
+		}
+	}
+
+	return setNumber(res, v)
+}
+
+// parseReplacements splits the argument of `replace:` into its old and new
+// pairs. Pairs are separated by ";" and written "old=>new", and a backslash
+// makes the following character literal, as in `\;` or `\=>`.
+func parseReplacements(arg string) ([][2]string, error) {
+	var pairs [][2]string
+	var cur [2]string
+	var b strings.Builder
+	half := 0
+
+	end := func() error {
+		cur[half] = b.String()
+		b.Reset()
+		if half == 0 {
+			return fmt.Errorf("replacement %q has no \"=>\"", cur[0])
+		}
+		pairs = append(pairs, cur)
+		cur, half = [2]string{}, 0
+		return nil
+	}
+
+	for i := 0; i < len(arg); i++ {
+		switch {
+		case arg[i] == '\\' && i+1 < len(arg):
+			i++
+			b.WriteByte(arg[i])
+		case arg[i] == ';':
+			if err := end(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(arg[i:], "=>") && half == 0:
+			cur[0] = b.String()
+			b.Reset()
+			half = 1
+			i++
+		default:
+			b.WriteByte(arg[i])
+		}
+	}
+	if err := end(); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// joinValues joins the non-empty values of the elements in the selection. The
+// argument of `join:` is the separator, optionally followed by ";"-separated
+// options, which may also be given as modifiers before it: `unique` drops
+// repeated values, and `sort` or `isort` sort them lexically, the latter
+// ignoring case.
+func (d *Decoder) joinValues(s *goquery.Selection, vf valFunc, tag goqueryTag, arg string) string {
+	opts := strings.Split(arg, ";")
+	sep := opts[0]
+	has := func(opt string) bool {
+		for _, o := range opts[1:] {
+			if o == opt {
+				return true
+			}
+		}
+		_, ok := tag.modifier(opt)
+		return ok
+	}
+
+	var vals []string
+	seen := map[string]bool{}
+	for i := range s.Nodes {
+		val := vf(d, s.Eq(i))
+		if val == "" || (has("unique") && seen[val]) {
+			continue
+		}
+		seen[val] = true
+		vals = append(vals, val)
+	}
+
+	switch {
+	case has("isort"):
+		sort.SliceStable(vals, func(i, j int) bool {
+			return strings.ToLower(vals[i]) < strings.ToLower(vals[j])
+		})
+	case has("sort"):
+		sort.Strings(vals)
+	}
+	return strings.Join(vals, sep)
+}
+
+// distinctCount counts the distinct non-empty values of the elements in the
+// selection, as rewritten by the modifiers of the tag, once whitespace has been
+// collapsed.
+func (d *Decoder) distinctCount(s *goquery.Selection, v reflect.Value, tag goqueryTag) (int, error) {
+	vf := tag.valFunc()
+	seen := map[string]bool{}
+	for i := range s.Nodes {
+		str, err := d.transformValue(vf(d, s.Eq(i)), v, tag)
+		if err != nil {
+			return 0, err
+		}
+		if str = collapseSpace(str); str != "" {
+			seen[str] = true
+		}
+	}
+	return len(seen), nil
+}
+
+// setMapEntry unmarshals the key string and value selection into a new map
+// entry of v.
+func (d *Decoder) setMapEntry(v reflect.Value, key string, val *goquery.Selection) error {
+	keyT, eleT := v.Type().Key(), v.Type().Elem()
+	newK, newV := reflect.New(TypeDeref(keyT)), reflect.New(TypeDeref(eleT))
+
+	if err := unmarshalLiteral(key, newK.Elem()); err != nil {
+		return &CannotUnmarshalError{
+			Reason:   mapKeyUnmarshalError,
+			V:        v,
+			Err:      err,
+			FldOrIdx: key,
+			Val:      key,
+		}
+	}
+
+	if err := d.unmarshalAtIndex(newK.Elem().Interface(), val, newV, ""); err != nil {
+		return &CannotUnmarshalError{
+			Reason:   typeConversionError,
+			V:        v,
+			Err:      err,
+			FldOrIdx: key,
+		}
+	}
+
+	if eleT.Kind() != reflect.Ptr {
+		newV = newV.Elem()
+	}
+	if keyT.Kind() != reflect.Ptr {
+		newK = newK.Elem()
+	}
+	v.SetMapIndex(newK, newV)
+	return nil
+}
+
+// unmarshalKV builds a map from repeated rows, each holding a key element
+// matched by the `key:` modifier and a value element matched by the `value:`
+// modifier. Rows without a key are skipped, and a repeated key replaces the
+// value of an earlier row.
+func (d *Decoder) unmarshalKV(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	keySel, ok := tag.modifier("key")
+	valSel, ok2 := tag.modifier("value")
+	if !ok || !ok2 || keySel == "" || valSel == "" {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    fmt.Errorf("kv requires both key: and value: selectors"),
+		}
+	}
+
+	for i := range s.Nodes {
+		row := s.Eq(i)
+		key := textVal(d, row.Find(keySel))
+		if key == "" {
+			continue
+		}
+		if err := d.setMapEntry(v, key, row.Find(valSel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmarshalDL builds a map from definition lists, keyed by the text of each
+// `dt`. The `dd`s following a run of `dt`s are the values of each of them, so
+// a slice value collects every `dd` and any other value uses the first. A `dd`
+// with no preceding `dt` is ignored. Groups wrapped in a `div` are supported.
+func (d *Decoder) unmarshalDL(s *goquery.Selection, v reflect.Value) error {
+	var keys []string
+	vals := map[string]*goquery.Selection{}
+
+	for i := range s.Nodes {
+		var pending []string
+		var lastDD bool
+
+		var visit func(*goquery.Selection)
+		visit = func(children *goquery.Selection) {
+			children.Each(func(_ int, c *goquery.Selection) {
+				switch goquery.NodeName(c) {
+				case "div":
+					visit(c.Children())
+				case "dt":
+					if lastDD {
+						pending, lastDD = nil, false
+					}
+					key := textVal(d, c)
+					if _, ok := vals[key]; !ok {
+						keys = append(keys, key)
+						vals[key] = &goquery.Selection{}
+					}
+					pending = append(pending, key)
+				case "dd":
+					lastDD = true
+					for _, key := range pending {
+						vals[key] = vals[key].AddSelection(c)
+					}
+				}
+			})
+		}
+		visit(s.Eq(i).Children())
+	}
+
+	multi := TypeDeref(v.Type().Elem()).Kind() == reflect.Slice
+	for _, key := range keys {
+		dds := vals[key]
+		if !multi {
+			dds = dds.First()
+		}
+		if err := d.setMapEntry(v, key, dds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmarshalIndexMap builds a map from the matched elements keyed by the
+// position of each among the elements the field's selector matched, so keys
+// keep their place when filters such as `where:` drop some matches.
+func (d *Decoder) unmarshalIndexMap(s *goquery.Selection, v reflect.Value) error {
+	pos := d.matched
+	d.matched = nil
+	for i, n := range s.Nodes {
+		key := i
+		if p, ok := pos[n]; ok {
+			key = p
+		}
+		if err := d.setMapEntry(v, strconv.Itoa(key), s.Eq(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmarshalGroupCount builds a map from each distinct value of the matched
+// elements, read with the value selector src, to the number of elements having
+// it, or by their text if src is empty. Elements with an empty value are
+// counted under emptyKey.
+func (d *Decoder) unmarshalGroupCount(s *goquery.Selection, v reflect.Value, src, emptyKey string) error {
+	vf := textVal
+	if src != "" {
+		vf = goqueryTag("," + src).valFunc()
+	}
+
+	var keys []string
+	counts := map[string]int{}
+	for i := range s.Nodes {
+		key := vf(d, s.Eq(i))
+		if key == "" {
+			key = emptyKey
+		}
+		if _, ok := counts[key]; !ok {
+			keys = append(keys, key)
+		}
+		counts[key]++
+	}
+
+	keyT, eleT := v.Type().Key(), v.Type().Elem()
+	for _, key := range keys {
+		newK, newV := reflect.New(keyT).Elem(), reflect.New(eleT).Elem()
+		if err := unmarshalLiteral(key, newK); err != nil {
+			return &CannotUnmarshalError{
+				Reason:   mapKeyUnmarshalError,
+				V:        v,
+				Err:      err,
+				FldOrIdx: key,
+				Val:      key,
+			}
+		}
+		if err := setNumber(float64(counts[key]), newV); err != nil {
+			return &CannotUnmarshalError{
+				Reason:   typeConversionError,
+				V:        v,
+				Err:      err,
+				FldOrIdx: key,
+			}
+		}
+		v.SetMapIndex(newK, newV)
+	}
+	return nil
+}
+
+// unmarshalDataStruct sets the fields of a struct from the `data-<prefix>-*`
+// attributes of the first matched element, matching the rest of each
+// attribute name, without its hyphens, to field names without regard to case.
+// Fields without a matching attribute keep their zero values.
+func (d *Decoder) unmarshalDataStruct(s *goquery.Selection, v reflect.Value, prefix string) error {
+	if len(s.Nodes) == 0 {
+		return nil
+	}
+
+	pfx := "data-" + strings.ToLower(prefix) + "-"
+	t := v.Type()
+	for _, attr := range s.Nodes[0].Attr {
+		if !strings.HasPrefix(attr.Key, pfx) {
+			continue
+		}
+		name := strings.ReplaceAll(attr.Key[len(pfx):], "-", "")
+		for j := 0; j < t.NumField(); j++ {
+			if !strings.EqualFold(t.Field(j).Name, name) || !v.Field(j).CanSet() {
+				continue
+			}
+			if err := unmarshalLiteral(attr.Val, v.Field(j)); err != nil {
+				return &CannotUnmarshalError{
+					V:        v,
+					Reason:   typeConversionError,
+					Err:      err,
+					Val:      attr.Val,
+					FldOrIdx: t.Field(j).Name,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// groupSelection splits the selection into runs of consecutive elements,
+// starting a new run at each element matching sep in document order. Elements
+// matching sep are not part of any run, and no empty runs are produced.
+func groupSelection(s *goquery.Selection, sep string) []*goquery.Selection {
+	if len(s.Nodes) == 0 {
+		return nil
+	}
+
+	items := map[*html.Node]bool{}
+	for _, n := range s.Nodes {
+		items[n] = true
+	}
+
+	root := s.Nodes[0]
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	seps := map[*html.Node]bool{}
+	for _, n := range NodeSelector([]*html.Node{root}).Find(sep).Nodes {
+		seps[n] = true
+	}
+
+	var groups []*goquery.Selection
+	cur := &goquery.Selection{}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case seps[n]:
+			if cur.Length() > 0 {
+				groups = append(groups, cur)
+				cur = &goquery.Selection{}
+			}
+			return
+		case items[n]:
+			cur = cur.AddNodes(n)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	if cur.Length() > 0 {
+		groups = append(groups, cur)
+	}
+	return groups
+}
+
+// unmarshalGroups unmarshals each run of elements between separators into an
+// element of a slice of slices.
+func (d *Decoder) unmarshalGroups(s *goquery.Selection, v reflect.Value, tag goqueryTag, sep string) error {
+	slice := v
+	eleT := v.Type().Elem()
+
+	for i, group := range groupSelection(s, sep) {
+		newV := reflect.New(TypeDeref(eleT))
+		if err := d.unmarshalAtIndex(i, group, newV, tag); err != nil {
+			return &CannotUnmarshalError{
+				Reason:   typeConversionError,
+				Err:      err,
+				V:        v,
+				FldOrIdx: i,
+			}
+		}
+		if eleT.Kind() != reflect.Ptr {
+			newV = newV.Elem()
+		}
+		v = reflect.Append(v, newV)
+	}
+
+	slice.Set(v)
+	return nil
+}
+
+// unmarshalStrings appends each string to the slice v as a literal element.
+func unmarshalStrings(strs []string, v reflect.Value) error {
+	slice := v
+	eleT := v.Type().Elem()
+
+	for i, str := range strs {
+		newV := reflect.New(TypeDeref(eleT))
+		if err := unmarshalLiteral(str, newV.Elem()); err != nil {
+			return &CannotUnmarshalError{
+				Reason:   typeConversionError,
+				Err:      err,
+				V:        v,
+				Val:      str,
+				FldOrIdx: i,
+			}
+		}
+		if eleT.Kind() != reflect.Ptr {
+			newV = newV.Elem()
+		}
+		v = reflect.Append(v, newV)
+	}
+
+	slice.Set(v)
+	return nil
+}
+
+// classTokens returns the class names of the matched elements that start with
+// prefix, in order.
+func classTokens(s *goquery.Selection, prefix string) []string {
+	var classes []string
+	for i := range s.Nodes {
+		class, _ := s.Eq(i).Attr("class")
+		for _, c := range strings.Fields(class) {
+			if strings.HasPrefix(c, prefix) {
+				classes = append(classes, c)
+			}
+		}
+	}
+	return classes
+}
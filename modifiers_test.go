@@ -0,0 +1,873 @@
+package goq
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+const tablePage = `<html><body>
+<table id="prices">
+	<tr><td class="name">Apple</td><td class="price">1.50</td></tr>
+	<tr><td class="name">Pear</td><td class="price">2.25</td></tr>
+	<tr><td class="name">Plum</td><td class="price">0.75</td></tr>
+	<tr><td class="name">Total</td><td class="price">n/a</td></tr>
+</table>
+</body></html>`
+
+func TestAggregate(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Sum   float64 `goquery:"#prices tr:not(:last-child) .price,agg:sum"`
+		Avg   float64 `goquery:"#prices tr:not(:last-child) .price,agg:avg"`
+		Min   float64 `goquery:"#prices .price,agg:min,skipinvalid"`
+		Max   float64 `goquery:"#prices .price,agg:max,skipinvalid"`
+		Count int     `goquery:"#prices .price,agg:count"`
+		None  int     `goquery:"#missing,agg:count"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(tablePage), &a))
+	asrt.Equal(4.5, a.Sum)
+	asrt.Equal(1.5, a.Avg)
+	asrt.Equal(0.75, a.Min)
+	asrt.Equal(2.25, a.Max)
+	asrt.Equal(4, a.Count)
+	asrt.Zero(a.None)
+}
+
+const dollarPage = `<html><body>
+<ul>
+	<li class="p">$1.50</li>
+	<li class="p">$2.25</li>
+	<li class="p">$1.50 </li>
+	<li class="w">Weight: 3kg</li>
+	<li class="w">Weight: 5kg</li>
+</ul>
+</body></html>`
+
+func TestAggregateTransforms(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Sum      float64 `goquery:".p,agg:sum,replace:$=>"`
+		Max      int     `goquery:".w,agg:max,regexp:(\\d+)kg"`
+		Distinct int     `goquery:".p,distinctcount,replace:$=>"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(dollarPage), &a))
+	asrt.Equal(5.25, a.Sum)
+	asrt.Equal(5, a.Max)
+	asrt.Equal(2, a.Distinct)
+}
+
+func TestAggregateErrors(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Sum float64 `goquery:"#prices .price,agg:sum"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(tablePage), &a)).unwind()
+	asrt.Equal("n/a", e.val)
+	asrt.Equal(typeConversionError, e.last().Reason)
+
+	var b struct {
+		Median float64 `goquery:"#prices .price,agg:median"`
+	}
+	e = checkErr(asrt, Unmarshal([]byte(tablePage), &b)).unwind()
+	asrt.Equal(invalidModifier, e.last().Reason)
+}
+
+const specPage = `<html><body>
+<div class="specs">
+	<div class="row"><span class="k">Weight</span><span class="v">12</span></div>
+	<div class="row"><span class="k">Colour</span><span class="v">Red</span></div>
+	<div class="row"><span class="k"></span><span class="v">Orphan</span></div>
+	<div class="row"><span class="k">Colour</span><span class="v">Blue</span></div>
+</div>
+</body></html>`
+
+func TestKV(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Specs  map[string]string `goquery:".specs .row,kv,key:.k,value:.v"`
+		Single map[string]int    `goquery:".specs .row:first-child,kv,key:.k,value:.v"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(specPage), &a))
+	asrt.Equal(map[string]string{"Weight": "12", "Colour": "Blue"}, a.Specs)
+	asrt.Equal(map[string]int{"Weight": 12}, a.Single)
+
+	var b struct {
+		Specs map[string]string `goquery:".specs .row,kv,key:.k"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(specPage), &b)).unwind()
+	asrt.Equal(invalidModifier, e.last().Reason)
+}
+
+const articlePage = `<html><body>
+<article>
+	<p class="intro">  The   quick brown
+		fox jumps.  </p>
+	<p class="accent">Crème brûlée</p>
+</article>
+</body></html>`
+
+func TestWordAndCharCount(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Words      int `goquery:".intro,wordcount"`
+		Chars      int `goquery:".intro,charcount"`
+		RuneChars  int `goquery:".accent,charcount"`
+		EmptyWords int `goquery:".missing,wordcount"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(articlePage), &a))
+	asrt.Equal(5, a.Words)
+	asrt.Equal(len("The quick brown fox jumps."), a.Chars)
+	asrt.Equal(12, a.RuneChars)
+	asrt.Equal(0, a.EmptyWords)
+}
+
+const threadPage = `<html><body>
+<div class="comment" id="c1">
+	<p>First</p>
+	<div class="reply">Agreed</div>
+	<div class="reply">Me too<div class="reply">Nested</div></div>
+</div>
+<div class="comment" id="c2"><p>Second</p></div>
+</body></html>`
+
+func TestCountFind(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Comments []struct {
+			ID      string `goquery:",id"`
+			Replies int    `goquery:",countfind:.reply"`
+		} `goquery:".comment"`
+		Paragraphs int `goquery:"body,countfind:p"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(threadPage), &a))
+	asrt.Len(a.Comments, 2)
+	asrt.Equal("c1", a.Comments[0].ID)
+	asrt.Equal(3, a.Comments[0].Replies)
+	asrt.Equal(0, a.Comments[1].Replies)
+	asrt.Equal(2, a.Paragraphs)
+}
+
+func TestExcludeText(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<span class="weight">12<small>kg</small></span>
+<span class="weight">7 <small>kg</small> <em class="badge">new</em></span>
+</body></html>`
+
+	var a struct {
+		Weight  int      `goquery:".weight:first-of-type,excludetext:small"`
+		Both    []string `goquery:".weight,excludetext:small"`
+		Full    string   `goquery:".weight:last-child"`
+		Missing string   `goquery:".missing,excludetext:small"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(12, a.Weight)
+	asrt.Equal([]string{"12", "7  new"}, a.Both)
+	asrt.Equal("7 kg new", a.Full)
+	asrt.Equal("", a.Missing)
+}
+
+func TestContents(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<p class="sig">  Jane <b>Doe</b><sup>1</sup>
+</p>
+<pre class="code">  a
+    b</pre><pre class="code">c  </pre>
+</body></html>`
+
+	var a struct {
+		Text     string `goquery:".sig,text"`
+		Contents string `goquery:".sig,contents"`
+		Code     string `goquery:".code,contents"`
+	}
+
+	d := NewDecoder(strings.NewReader(page))
+	d.IgnoreTags = []string{"sup"}
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal("Jane Doe", a.Text)
+	asrt.Equal("  Jane Doe\n", a.Contents)
+	asrt.Equal("  a\n    bc  ", a.Code)
+}
+
+func TestFlag(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<li class="tab" data-active="true">One</li>
+<li class="tab" data-active="1">Two</li>
+<li class="tab">Three</li>
+<li class="tab" data-active="false">Four</li>
+<li class="tab" data-active>Five</li>
+<li class="tab" data-active="Y">Six</li>
+</body></html>`
+
+	var a struct {
+		Active []bool `goquery:".tab,flag:data-active"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal([]bool{true, true, false, false, true, false}, a.Active)
+
+	d := NewDecoder(strings.NewReader(page))
+	d.TrueFlagValues = []string{"y", "true"}
+	var b struct {
+		Active []bool `goquery:".tab,flag:data-active"`
+	}
+	asrt.NoError(d.Decode(&b))
+	asrt.Equal([]bool{true, false, false, false, false, true}, b.Active)
+}
+
+func TestSlug(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<h2>Hello, World!</h2>
+<h2>  Don't   panic -- it's  2024 </h2>
+<h2>Crème Brûlée &amp; Café</h2>
+<h2>!!!</h2>
+</body></html>`
+
+	var a struct {
+		First string   `goquery:"h2:first-of-type,slug"`
+		All   []string `goquery:"h2,slug"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal("hello-world", a.First)
+	asrt.Equal([]string{"hello-world", "dont-panic-its-2024", "crème-brûlée-café", ""}, a.All)
+}
+
+func TestTruncate(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<p class="long">The quick   brown
+	fox jumps over the lazy dog</p>
+<p class="cafe">Café crème brûlée</p>
+<p class="short"> Hi there </p>
+</body></html>`
+
+	var a struct {
+		Long  string `goquery:".long,truncate:19"`
+		Cafe  string `goquery:".cafe,truncate:8"`
+		Short string `goquery:".short,truncate:20"`
+		Exact string `goquery:".short,truncate:8"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal("The quick brown fox…", a.Long)
+	asrt.Equal("Café crè…", a.Cafe)
+	asrt.Equal("Hi there", a.Short)
+	asrt.Equal("Hi there", a.Exact)
+
+	var b struct {
+		Long string `goquery:".long,truncate:many"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(page), &b))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}
+
+func TestDataStruct(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<div class="card" data-user-name="Ada" data-user-age="36" data-user-first-name="Augusta"
+	data-user-admin="true" data-account-id="7" data-user="ignored"></div>
+<div class="bad" data-user-age="old"></div>
+</body></html>`
+
+	type user struct {
+		Name      string
+		FirstName string
+		Age       int
+		Admin     bool
+		Email     string
+	}
+	var a struct {
+		User    user  `goquery:".card,datastruct:user"`
+		Missing *user `goquery:".missing,datastruct:user"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(user{Name: "Ada", FirstName: "Augusta", Age: 36, Admin: true}, a.User)
+
+	var b struct {
+		User user `goquery:".bad,datastruct:user"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(page), &b))
+	asrt.Equal(typeConversionError, e.unwind().last().Reason)
+}
+
+func TestPreserve(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<pre class="log">
+$ go test
+  ok   github.com/roryq/goq
+PASS
+</pre>
+<div class="post">
+  <p>First   paragraph</p>
+  <p>Second<br>line</p>
+  <ul><li>one</li><li>two</li></ul>
+</div>
+<pre><code class="go">func main() {
+	fmt.Println("hi")
+}</code></pre>
+</body></html>`
+
+	var a struct {
+		Log  string `goquery:".log,preserve"`
+		Post string `goquery:".post,preserve"`
+		Code string `goquery:"code.go,preserve"`
+		Flat string `goquery:".post"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal("$ go test\n  ok   github.com/roryq/goq\nPASS", a.Log)
+	asrt.Equal("First paragraph\nSecond\nline\none\ntwo", a.Post)
+	asrt.Equal("func main() {\n\tfmt.Println(\"hi\")\n}", a.Code)
+	asrt.NotContains(a.Flat, "Second\nline")
+}
+
+func TestGroupCount(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body><ul>
+<li class="item" data-category="books">A</li>
+<li class="item" data-category="music">B</li>
+<li class="item" data-category="books">C</li>
+<li class="item" data-category="">D</li>
+<li class="item">E</li>
+<li class="item" data-category="books">F</li>
+</ul></body></html>`
+
+	var a struct {
+		Facets map[string]int `goquery:".item,groupcount:[data-category],emptykey:other"`
+		Plain  map[string]int `goquery:".item,groupcount:[data-category]"`
+		ByText map[string]int `goquery:".item,groupcount:text"`
+		Bare   map[string]int `goquery:".item,groupcount:"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(map[string]int{"books": 3, "music": 1, "other": 2}, a.Facets)
+	asrt.Equal(map[string]int{"books": 3, "music": 1, "": 2}, a.Plain)
+	asrt.Len(a.ByText, 6)
+	asrt.Equal(a.ByText, a.Bare)
+}
+
+func TestAttrEq(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<img class="pic" src="a.jpg" loading="lazy">
+<img class="pic" src="b.jpg" loading="eager">
+<img class="pic" src="c.jpg">
+<video class="clip" preload=""></video>
+</body></html>`
+
+	var a struct {
+		Lazy    []bool `goquery:".pic,attreq:loading=lazy"`
+		Preload bool   `goquery:".clip,attreq:preload="`
+		Eager   bool   `goquery:".pic,attreq:loading=eager"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal([]bool{true, false, false}, a.Lazy)
+	asrt.True(a.Preload)
+	asrt.False(a.Eager)
+}
+
+func TestCountAttr(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<img src="a.png" alt="A">
+<img src="b.png" alt="">
+<img src="c.png">
+<img src="d.png">
+<img src="e.png">
+</body></html>`
+
+	var a struct {
+		WithAlt    int `goquery:"img,countattr:alt"`
+		WithoutAlt int `goquery:"img,countattr:alt,invert"`
+		Links      int `goquery:"a,countattr:href"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(2, a.WithAlt)
+	asrt.Equal(3, a.WithoutAlt)
+	asrt.Equal(0, a.Links)
+}
+
+func TestIsUnique(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		One     bool `goquery:"#c1,isunique"`
+		Several bool `goquery:".reply,isunique"`
+		None    bool `goquery:".missing,isunique"`
+		Nested  []struct {
+			Para bool `goquery:"p,isunique"`
+		} `goquery:".comment"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(threadPage), &a))
+	asrt.True(a.One)
+	asrt.False(a.Several)
+	asrt.False(a.None)
+	asrt.Len(a.Nested, 2)
+	asrt.True(a.Nested[0].Para)
+	asrt.True(a.Nested[1].Para)
+}
+
+func TestJoin(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<span class="tag">go</span>
+<span class="tag">html</span>
+<span class="tag">Bytes</span>
+<span class="tag">go</span>
+<span class="tag"></span>
+<span class="tag">css</span>
+<span class="tag">apple</span>
+</body></html>`
+
+	var a struct {
+		Sorted    string `goquery:".tag,join:,;unique;sort"`
+		Folded    string `goquery:".tag,join:, ;unique;isort"`
+		Modifiers string `goquery:".tag,unique,sort,join: | "`
+		Plain     string `goquery:".tag,join:/"`
+		None      string `goquery:".missing,join:,"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal("Bytes,apple,css,go,html", a.Sorted)
+	asrt.Equal("apple, Bytes, css, go, html", a.Folded)
+	asrt.Equal("Bytes | apple | css | go | html", a.Modifiers)
+	asrt.Equal("go/html/Bytes/go/css/apple", a.Plain)
+	asrt.Equal("", a.None)
+}
+
+func TestDistinctCount(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<div class="post"><span class="author" data-id="1">Alice</span></div>
+<div class="post"><span class="author" data-id="2">Bob</span></div>
+<div class="post"><span class="author" data-id="1"> Alice </span></div>
+<div class="post"><span class="author" data-id="3">Carol  Ann</span></div>
+<div class="post"><span class="author" data-id="3">Carol Ann</span></div>
+<div class="post"><span class="author"></span></div>
+</body></html>`
+
+	var a struct {
+		Authors int     `goquery:".author,distinctcount"`
+		IDs     uint    `goquery:".author,[data-id],distinctcount"`
+		Ratio   float64 `goquery:".author,distinctcount"`
+		None    int     `goquery:".missing,distinctcount"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(3, a.Authors)
+	asrt.Equal(uint(3), a.IDs)
+	asrt.Equal(3.0, a.Ratio)
+	asrt.Equal(0, a.None)
+}
+
+func TestHasInlineHandler(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<a class="link" href="/safe">Safe</a>
+<a class="link" href="#" onclick="track()">Tracked</a>
+<img class="pic" src="x.png" ONERROR="alert(1)">
+<div class="plain" data-onclick="1"></div>
+</body></html>`
+
+	var a struct {
+		Links   []bool `goquery:".link,hasinlinehandler"`
+		Image   bool   `goquery:".pic,hasinlinehandler"`
+		Plain   bool   `goquery:".plain,hasinlinehandler"`
+		Missing bool   `goquery:".missing,hasinlinehandler"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal([]bool{false, true}, a.Links)
+	asrt.True(a.Image)
+	asrt.False(a.Plain)
+	asrt.False(a.Missing)
+}
+
+func TestIndexMap(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<ol>
+	<li>zero</li><li>one</li><li>two</li><li>three</li><li>four</li>
+</ol>
+</body></html>`
+
+	var a struct {
+		All  map[int]string    `goquery:"li,indexmap"`
+		Odd  map[int]string    `goquery:"li:nth-child(odd),indexmap"`
+		Long map[string]string `goquery:"li,where:long,indexmap"`
+	}
+
+	d := NewDecoder(strings.NewReader(page))
+	d.RegisterFilter("long", func(s *goquery.Selection) bool { return len(s.Text()) > 3 })
+	asrt.NoError(d.Decode(&a))
+	asrt.Len(a.All, 5)
+	asrt.Equal("three", a.All[3])
+	asrt.Equal(map[int]string{0: "zero", 1: "two", 2: "four"}, a.Odd)
+	asrt.Equal(map[string]string{"0": "zero", "3": "three", "4": "four"}, a.Long)
+
+	const lists = `<html><body>
+<ul><li>a</li><li>b</li></ul>
+<ul><h3>More</h3><li>c</li><li>d</li></ul>
+</body></html>`
+
+	var b struct {
+		All  map[int]string `goquery:"li,indexmap"`
+		NotB map[int]string `goquery:"li,where:notb,indexmap"`
+	}
+	d = NewDecoder(strings.NewReader(lists))
+	d.RegisterFilter("notb", func(s *goquery.Selection) bool { return s.Text() != "b" })
+	asrt.NoError(d.Decode(&b))
+	asrt.Equal(map[int]string{0: "a", 1: "b", 2: "c", 3: "d"}, b.All)
+	asrt.Equal(map[int]string{0: "a", 2: "c", 3: "d"}, b.NotB)
+}
+
+func TestReplace(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<span class="rating">Rating: 4,5</span>
+<span class="rating">Rating: 3,0</span>
+<span class="sep">a;b=>c</span>
+</body></html>`
+
+	var a struct {
+		Rating  float64   `goquery:".rating:first-of-type,replace:Rating:=>;,=>."`
+		Ratings []float64 `goquery:".rating,replace:Rating:=>;,=>."`
+		Escaped string    `goquery:".sep,replace:\\;=> and ;\\=>=>to"`
+		Removed string    `goquery:".sep,replace:b=>"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(4.5, a.Rating)
+	asrt.Equal([]float64{4.5, 3.0}, a.Ratings)
+	asrt.Equal("a and btoc", a.Escaped)
+	asrt.Equal("a;=>c", a.Removed)
+
+	var b struct {
+		Bad string `goquery:".sep,replace:a;b=>c"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(page), &b))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}
+
+const dlPage = `<html><body>
+<dl id="simple">
+	<dt>Colour</dt><dd>Red</dd>
+	<dt>Weight</dt><dd>12</dd>
+</dl>
+<dl id="multi">
+	<dd>Orphan</dd>
+	<dt>Authors</dt>
+	<dd>Alice</dd>
+	<dd>Bob</dd>
+	<dt>Editor</dt>
+	<dt>Reviewer</dt>
+	<dd>Carol</dd>
+	<div><dt>Publisher</dt><dd>ACME</dd></div>
+	<dt>Empty</dt>
+</dl>
+</body></html>`
+
+func TestDefinitionList(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Simple map[string]string   `goquery:"#simple,dl"`
+		Multi  map[string][]string `goquery:"#multi,dl"`
+		First  map[string]string   `goquery:"#multi,dl"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(dlPage), &a))
+	asrt.Equal(map[string]string{"Colour": "Red", "Weight": "12"}, a.Simple)
+	asrt.Equal(map[string][]string{
+		"Authors":   {"Alice", "Bob"},
+		"Editor":    {"Carol"},
+		"Reviewer":  {"Carol"},
+		"Publisher": {"ACME"},
+		"Empty":     nil,
+	}, a.Multi)
+	asrt.Equal("Alice", a.First["Authors"])
+	asrt.Equal("", a.First["Empty"])
+}
+
+const dirPage = `<html><body>
+<div dir="RTL">
+	<ul><li class="msg">مرحبا</li></ul>
+	<p class="msg" dir="ltr">Hello</p>
+</div>
+<p class="plain">Plain</p>
+</body></html>`
+
+func TestDir(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Dirs  []string `goquery:".msg,dir"`
+		Plain string   `goquery:".plain,dir"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(dirPage), &a))
+	asrt.Equal([]string{"rtl", "ltr"}, a.Dirs)
+	asrt.Equal("ltr", a.Plain)
+}
+
+func TestIsRTL(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<p class="ar">مرحبا بالعالم</p>
+<p class="he">שלום עולם 2024!</p>
+<p class="en">Hello world</p>
+<p class="mixed">Go مرحبا language</p>
+<p class="digits">12 345</p>
+</body></html>`
+
+	var a struct {
+		Arabic  bool `goquery:".ar,isrtl"`
+		Hebrew  bool `goquery:".he,isrtl"`
+		Latin   bool `goquery:".en,isrtl"`
+		Mixed   bool `goquery:".mixed,isrtl"`
+		Digits  bool `goquery:".digits,isrtl"`
+		Missing bool `goquery:".missing,isrtl"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.True(a.Arabic)
+	asrt.True(a.Hebrew)
+	asrt.False(a.Latin)
+	asrt.False(a.Mixed)
+	asrt.False(a.Digits)
+	asrt.False(a.Missing)
+}
+
+const groupPage = `<html><body>
+<div class="feed">
+	<hr>
+	<p class="item">A</p>
+	<p class="item">B</p>
+	<hr>
+	<hr>
+	<div><p class="item">C</p></div>
+</div>
+</body></html>`
+
+func TestGroupBy(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Groups [][]string `goquery:".feed .item,groupby:hr"`
+		Items  [][]review `goquery:".feed .item,groupby:hr"`
+		None   [][]string `goquery:".missing,groupby:hr"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(groupPage), &a))
+	asrt.Equal([][]string{{"A", "B"}, {"C"}}, a.Groups)
+	asrt.Len(a.Items, 2)
+	asrt.Equal("C", a.Items[1][0].Text)
+	asrt.Empty(a.None)
+}
+
+const classPage = `<html><body>
+<div class="card  state-active layout-wide state-featured"></div>
+<div class="plain"></div>
+</body></html>`
+
+func TestClasses(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		All    []string `goquery:".card,classes"`
+		States []string `goquery:".card,classes:state-"`
+		None   []string `goquery:".plain,classes:state-"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(classPage), &a))
+	asrt.Equal([]string{"card", "state-active", "layout-wide", "state-featured"}, a.All)
+	asrt.Equal([]string{"state-active", "state-featured"}, a.States)
+	asrt.Empty(a.None)
+}
+
+const weightPage = `<html><body>
+<ul>
+	<li class="item" data-weight="1.5">A</li>
+	<li class="item" data-weight="2">B</li>
+	<li class="item" data-weight="heavy">C</li>
+	<li class="item">D</li>
+	<li class="item" data-weight="0.5">E</li>
+</ul>
+</body></html>`
+
+func TestAggregateAttribute(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Total float64 `goquery:".item,[data-weight],agg:sum,skipinvalid"`
+		Max   int     `goquery:".item,[data-weight],agg:max,skipinvalid"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(weightPage), &a))
+	asrt.Equal(4.0, a.Total)
+	asrt.Equal(2, a.Max)
+
+	var b struct {
+		Total float64 `goquery:".item,[data-weight],agg:sum"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(weightPage), &b)).unwind()
+	asrt.Equal("heavy", e.val)
+}
+
+func TestAttrsJSON(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<a id="home" href="/?q=&quot;x&quot;" data-track="nav" class="link">Home</a>
+<b>Bold</b>
+</body></html>`
+
+	var a struct {
+		Link    string `goquery:"a,attrsjson"`
+		Bare    string `goquery:"b,attrsjson"`
+		Missing string `goquery:"i,attrsjson"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(`{"class":"link","data-track":"nav","href":"/?q=\"x\"","id":"home"}`, a.Link)
+	asrt.Equal(`{}`, a.Bare)
+	asrt.Equal("", a.Missing)
+}
+
+func TestRatio(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<progress id="half" value="30" max="60"></progress>
+<progress id="nomax" value="0.25"></progress>
+<progress id="zero" value="3" max="0"></progress>
+<meter id="meter" min="10" max="20" value="15"></meter>
+<meter id="over" max="10" value="50"></meter>
+</body></html>`
+
+	var a struct {
+		Half  float64 `goquery:"#half,ratio"`
+		NoMax float64 `goquery:"#nomax,ratio"`
+		Zero  float64 `goquery:"#zero,ratio"`
+		Meter float32 `goquery:"#meter,ratio"`
+		Over  float64 `goquery:"#over,ratio"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(0.5, a.Half)
+	asrt.Equal(0.25, a.NoMax)
+	asrt.Equal(1.0, a.Zero)
+	asrt.Equal(float32(0.5), a.Meter)
+	asrt.Equal(1.0, a.Over)
+}
+
+func TestClamp(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<meter id="over" min="0" max="100" value="140"></meter>
+<meter id="under" min="10" max="100" value="-5"></meter>
+<meter id="inside" min="0" max="100" value="42.5"></meter>
+<input type="range" id="nomax" min="1" value="900">
+<meter id="novalue" min="5" max="10"></meter>
+</body></html>`
+
+	var a struct {
+		Over    int     `goquery:"#over,clamp"`
+		Under   int     `goquery:"#under,clamp"`
+		Inside  float64 `goquery:"#inside,clamp"`
+		NoMax   int     `goquery:"#nomax,clamp"`
+		NoValue int     `goquery:"#novalue,clamp"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(100, a.Over)
+	asrt.Equal(10, a.Under)
+	asrt.Equal(42.5, a.Inside)
+	asrt.Equal(900, a.NoMax)
+	asrt.Equal(5, a.NoValue)
+}
+
+func TestPositionFraction(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<ol><li>a</li><li>b</li><li class="mid">c</li><li>d</li><li class="last">e</li></ol>
+<ul><li class="only">x</li></ul>
+</body></html>`
+
+	var a struct {
+		Mid     float64 `goquery:".mid,positionfraction"`
+		Last    float64 `goquery:".last,positionfraction"`
+		Only    float64 `goquery:".only,positionfraction"`
+		Missing float64 `goquery:".missing,positionfraction"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(0.5, a.Mid)
+	asrt.Equal(1.0, a.Last)
+	asrt.Zero(a.Only)
+	asrt.Zero(a.Missing)
+}
+
+func TestDimension(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<img id="photo" src="a.jpg" width="640" height="480px">
+<img id="thumb" src="b.jpg" width=" 64 PX ">
+</body></html>`
+
+	var a struct {
+		Width       int     `goquery:"#photo,dimension:width"`
+		Height      int     `goquery:"#photo,dimension:height"`
+		ThumbWidth  uint    `goquery:"#thumb,dimension:width"`
+		ThumbHeight float64 `goquery:"#thumb,dimension:height"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(640, a.Width)
+	asrt.Equal(480, a.Height)
+	asrt.Equal(uint(64), a.ThumbWidth)
+	asrt.Equal(0.0, a.ThumbHeight)
+}
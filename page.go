@@ -0,0 +1,284 @@
+package goq
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// NextPage returns the URL of the next page of a paginated document, taken
+// from the first `<a>` or `<link>` with a rel of "next" and resolved against
+// base, which may be nil. It returns a nil URL if the document has no such
+// link.
+func NextPage(bs []byte, base *url.URL) (*url.URL, error) {
+	var p struct {
+		Next *url.URL `goquery:"[rel~=next][href],[href]"`
+	}
+
+	d := NewDecoder(bytes.NewReader(bs))
+	d.BaseURL = base
+	if err := d.Decode(&p); err != nil {
+		return nil, err
+	}
+	if *p.Next == (url.URL{}) {
+		return nil, nil
+	}
+	return p.Next, nil
+}
+
+// Canonical returns the canonical URL of a document, resolved against base,
+// which may be nil. It is taken from the href of a `<link rel="canonical">`,
+// or failing that the content of the og:url meta property. It returns a nil
+// URL if the document has neither.
+func Canonical(bs []byte, base *url.URL) (*url.URL, error) {
+	var p struct {
+		Link url.URL `goquery:"link[rel~=canonical][href],[href]"`
+		OG   url.URL `goquery:"meta[property='og:url'],[content]"`
+	}
+
+	d := NewDecoder(bytes.NewReader(bs))
+	d.BaseURL = base
+	if err := d.Decode(&p); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.Link != (url.URL{}):
+		return &p.Link, nil
+	case p.OG != (url.URL{}):
+		return &p.OG, nil
+	}
+	return nil, nil
+}
+
+// publishedSources are the elements PublishedTime reads a date from, in order
+// of precedence.
+var publishedSources = []struct{ sel, attr string }{
+	{`meta[property="article:published_time"]`, "content"},
+	{`meta[property="og:updated_time"]`, "content"},
+	{"time[datetime]", "datetime"},
+}
+
+// PublishedTime returns the publication date of a document. It is read from
+// the first of these that is present and non-empty: the article:published_time
+// meta property, the og:updated_time meta property, and the datetime attribute
+// of the first `<time>` element. The value is parsed with the same layouts as
+// time.Time fields, and times without a zone are taken to be UTC. It returns
+// the zero time if the document has none of them.
+func PublishedTime(bs []byte) (time.Time, error) {
+	d := NewDecoder(bytes.NewReader(bs))
+	if d.err != nil {
+		return time.Time{}, d.err
+	}
+
+	for _, src := range publishedSources {
+		str := strings.TrimSpace(d.doc.Find(src.sel).First().AttrOr(src.attr, ""))
+		if str != "" {
+			return d.parseTime(str, "")
+		}
+	}
+	return time.Time{}, nil
+}
+
+// DefaultErrorPageSignals are the phrases looked for by IsErrorPage unless
+// Decoder.ErrorPageSignals is set.
+var DefaultErrorPageSignals = []string{
+	"404",
+	"not found",
+	"page does not exist",
+	"page doesn't exist",
+	"no longer available",
+}
+
+// IsErrorPage reports whether a document looks like an error page, such as a
+// "soft 404" served with a successful status: its body has no text, or its
+// title or first `<h1>` contains one of DefaultErrorPageSignals, ignoring
+// case. A document that cannot be parsed is not reported as one.
+func IsErrorPage(bs []byte) bool {
+	d := NewDecoder(bytes.NewReader(bs))
+	if d.err != nil {
+		return false
+	}
+	return d.isErrorPage(d.doc.Selection)
+}
+
+// isErrorPage reports whether the document holding s has no text in its body,
+// or has one of the decoder's error page signals in its title or first
+// heading, ignoring case.
+func (d *Decoder) isErrorPage(s *goquery.Selection) bool {
+	root := rootSelection(s)
+	if strings.TrimSpace(d.text(root.Find("body"))) == "" {
+		return true
+	}
+
+	signals := d.ErrorPageSignals
+	if signals == nil {
+		signals = DefaultErrorPageSignals
+	}
+	for _, sel := range []string{"title", "h1"} {
+		text := strings.ToLower(textVal(d, root.Find(sel).First()))
+		for _, sig := range signals {
+			if text != "" && strings.Contains(text, strings.ToLower(sig)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isErrorPageVal gives "true" if the document holding the selection looks like
+// an error page.
+func isErrorPageVal(d *Decoder, s *goquery.Selection) string {
+	return strconv.FormatBool(d.isErrorPage(s))
+}
+
+// structuredData selects the markers of structured data: JSON-LD scripts,
+// microdata items and RDFa types.
+const structuredData = `script[type*="ld+json"], [itemscope], [typeof]`
+
+// HasStructuredData reports whether a document holds any structured data, as
+// JSON-LD, microdata or RDFa. A document that cannot be parsed has none.
+func HasStructuredData(bs []byte) bool {
+	d := NewDecoder(bytes.NewReader(bs))
+	if d.err != nil {
+		return false
+	}
+	return d.doc.Find(structuredData).Length() > 0
+}
+
+// charsetVal gives the character encoding the document holding the selection
+// declares, from `<meta charset>` or else an http-equiv Content-Type meta
+// tag, in lower case. It is empty if the document declares none.
+func charsetVal(_ *Decoder, s *goquery.Selection) string {
+	root := rootSelection(s)
+	if cs := strings.TrimSpace(root.Find("meta[charset]").First().AttrOr("charset", "")); cs != "" {
+		return strings.ToLower(cs)
+	}
+
+	var cs string
+	root.Find("meta[http-equiv][content]").EachWithBreak(func(_ int, m *goquery.Selection) bool {
+		if !strings.EqualFold(m.AttrOr("http-equiv", ""), "content-type") {
+			return true
+		}
+		if _, params, err := mime.ParseMediaType(m.AttrOr("content", "")); err == nil {
+			cs = strings.ToLower(params["charset"])
+		}
+		return false
+	})
+	return cs
+}
+
+// breadcrumbs returns the trail of a breadcrumb container: the text of each of
+// its links in order, followed by the current page if it is marked with
+// aria-current but is not a link. If the selection holds no trail, the first
+// JSON-LD BreadcrumbList of the document is used instead.
+func (d *Decoder) breadcrumbs(s *goquery.Selection) []string {
+	var trail []string
+	links := s.Find("a")
+	for i := range links.Nodes {
+		if str := textVal(d, links.Eq(i)); str != "" {
+			trail = append(trail, collapseSpace(str))
+		}
+	}
+	cur := s.Find("[aria-current]").Last()
+	if cur.Length() > 0 && !cur.Is("a") && cur.Find("a").Length() == 0 {
+		if str := textVal(d, cur); str != "" {
+			trail = append(trail, collapseSpace(str))
+		}
+	}
+	if len(trail) > 0 {
+		return trail
+	}
+
+	var root *goquery.Selection
+	switch {
+	case d.doc != nil:
+		root = d.doc.Selection
+	case len(s.Nodes) > 0:
+		root = rootSelection(s)
+	default:
+		return nil
+	}
+	scripts := root.Find(`script[type="application/ld+json"]`)
+	for i := range scripts.Nodes {
+		var data interface{}
+		if json.Unmarshal([]byte(scripts.Eq(i).Text()), &data) != nil {
+			continue
+		}
+		if list := findBreadcrumbList(data); list != nil {
+			return breadcrumbListNames(list)
+		}
+	}
+	return nil
+}
+
+// findBreadcrumbList searches decoded JSON-LD, including arrays and @graph,
+// for an object whose @type is BreadcrumbList.
+func findBreadcrumbList(data interface{}) map[string]interface{} {
+	switch data := data.(type) {
+	case []interface{}:
+		for _, item := range data {
+			if list := findBreadcrumbList(item); list != nil {
+				return list
+			}
+		}
+	case map[string]interface{}:
+		if data["@type"] == "BreadcrumbList" {
+			return data
+		}
+		return findBreadcrumbList(data["@graph"])
+	}
+	return nil
+}
+
+// breadcrumbListNames gives the names of the items of a BreadcrumbList ordered
+// by position. An item is named by its own name or that of its item.
+func breadcrumbListNames(list map[string]interface{}) []string {
+	items, _ := list["itemListElement"].([]interface{})
+	type crumb struct {
+		pos  float64
+		name string
+	}
+	var crumbs []crumb
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		if inner, ok := m["item"].(map[string]interface{}); ok && name == "" {
+			name, _ = inner["name"].(string)
+		}
+		if name = collapseSpace(name); name == "" {
+			continue
+		}
+		var pos float64
+		switch p := m["position"].(type) {
+		case float64:
+			pos = p
+		case string:
+			pos, _ = strconv.ParseFloat(p, 64)
+		}
+		crumbs = append(crumbs, crumb{pos, name})
+	}
+
+	sort.SliceStable(crumbs, func(i, j int) bool { return crumbs[i].pos < crumbs[j].pos })
+	names := make([]string, len(crumbs))
+	for i, c := range crumbs {
+		names[i] = c.name
+	}
+	return names
+}
+
+// unmarshalBreadcrumbs fills the slice v with the breadcrumb trail of the
+// selection.
+func (d *Decoder) unmarshalBreadcrumbs(s *goquery.Selection, v reflect.Value) error {
+	return unmarshalStrings(d.breadcrumbs(s), v)
+}
@@ -0,0 +1,208 @@
+package goq
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const paginatedPage = `<html><head>
+<title>Results</title>
+</head><body>
+<ul class="results"><li>One</li></ul>
+<nav class="pager">
+	<a rel="prev" href="?page=1">Previous</a>
+	<a rel="next nofollow" href="?page=3">Load more</a>
+</nav>
+</body></html>`
+
+func TestNextPageField(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Next *url.URL `goquery:"a[rel~=next],[href]"`
+	}
+
+	base, _ := url.Parse("https://example.com/search?q=go&page=2")
+	d := NewDecoder(strings.NewReader(paginatedPage))
+	d.BaseURL = base
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal("https://example.com/search?page=3", a.Next.String())
+}
+
+func TestNextPage(t *testing.T) {
+	asrt := assert.New(t)
+
+	base, _ := url.Parse("https://example.com/search?q=go&page=2")
+	next, err := NextPage([]byte(paginatedPage), base)
+	asrt.NoError(err)
+	asrt.Equal("https://example.com/search?page=3", next.String())
+
+	next, err = NextPage([]byte(`<html><head><link rel="next" href="/p/2"></head></html>`), nil)
+	asrt.NoError(err)
+	asrt.Equal("/p/2", next.String())
+
+	next, err = NextPage([]byte(testPage), base)
+	asrt.NoError(err)
+	asrt.Nil(next)
+}
+
+func TestCanonical(t *testing.T) {
+	asrt := assert.New(t)
+
+	base, _ := url.Parse("https://example.com/products/anvil?ref=home")
+
+	u, err := Canonical([]byte(`<html><head>
+<meta property="og:url" content="https://example.com/og/anvil">
+<link rel="canonical" href="/products/anvil">
+</head></html>`), base)
+	asrt.NoError(err)
+	asrt.Equal("https://example.com/products/anvil", u.String())
+
+	u, err = Canonical([]byte(`<html><head>
+<meta property="og:url" content="https://example.com/og/anvil">
+</head></html>`), base)
+	asrt.NoError(err)
+	asrt.Equal("https://example.com/og/anvil", u.String())
+
+	u, err = Canonical([]byte(testPage), base)
+	asrt.NoError(err)
+	asrt.Nil(u)
+}
+
+const publishedPage = `<html><head>
+<meta property="og:updated_time" content="2024-03-05T09:00:00Z">
+<meta property="article:published_time" content="2024-03-01T12:30:00+01:00">
+</head><body>
+<time datetime="2020-01-01">Long ago</time>
+</body></html>`
+
+func TestPublishedTime(t *testing.T) {
+	asrt := assert.New(t)
+
+	pub, err := PublishedTime([]byte(publishedPage))
+	asrt.NoError(err)
+	asrt.True(time.Date(2024, 3, 1, 11, 30, 0, 0, time.UTC).Equal(pub))
+
+	pub, err = PublishedTime([]byte(`<html><body><p>Posted <time datetime="2023-07-14">Bastille Day</time></p></body></html>`))
+	asrt.NoError(err)
+	asrt.Equal(time.Date(2023, 7, 14, 0, 0, 0, 0, time.UTC), pub)
+
+	pub, err = PublishedTime([]byte(testPage))
+	asrt.NoError(err)
+	asrt.True(pub.IsZero())
+
+	_, err = PublishedTime([]byte(`<meta property="article:published_time" content="last week">`))
+	asrt.Error(err)
+}
+
+const soft404Page = `<html><head><title>Oops! Page Not Found | ACME</title></head>
+<body><nav><a href="/">Home</a></nav><p>Try searching instead.</p></body></html>`
+
+func TestIsErrorPage(t *testing.T) {
+	asrt := assert.New(t)
+
+	asrt.True(IsErrorPage([]byte(soft404Page)))
+	asrt.True(IsErrorPage([]byte(`<html><body><h1>Error 404</h1></body></html>`)))
+	asrt.True(IsErrorPage([]byte(`<html><head><title>ACME</title></head><body> </body></html>`)))
+	asrt.False(IsErrorPage([]byte(paginatedPage)))
+	asrt.False(IsErrorPage([]byte(testPage)))
+
+	var a struct {
+		Error bool `goquery:",iserrorpage"`
+		Found bool `goquery:"nav a,iserrorpage"`
+	}
+	asrt.NoError(Unmarshal([]byte(soft404Page), &a))
+	asrt.True(a.Error)
+	asrt.True(a.Found)
+
+	d := NewDecoder(strings.NewReader(soft404Page))
+	d.ErrorPageSignals = []string{"gone"}
+	asrt.NoError(d.Decode(&a))
+	asrt.False(a.Error)
+
+	d = NewDecoder(strings.NewReader(`<html><head><title>This Page Is Gone</title></head><body>x</body></html>`))
+	d.ErrorPageSignals = []string{"gone"}
+	asrt.NoError(d.Decode(&a))
+	asrt.True(a.Error)
+}
+
+func TestCharset(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Charset string `goquery:",charset"`
+		FromP   string `goquery:"p,charset"`
+	}
+
+	latin1 := []byte("<html><head><meta charset=\"Windows-1252\"></head><body><p>Caf\xe9</p></body></html>")
+	asrt.NoError(Unmarshal(latin1, &a))
+	asrt.Equal("windows-1252", a.Charset)
+	asrt.Equal("windows-1252", a.FromP)
+
+	var b struct {
+		Charset string `goquery:",charset"`
+	}
+	asrt.NoError(Unmarshal([]byte(`<html><head>
+<meta http-equiv="Content-Type" content="text/html; charset=ISO-8859-1">
+</head><body></body></html>`), &b))
+	asrt.Equal("iso-8859-1", b.Charset)
+
+	var c struct {
+		Charset string `goquery:",charset"`
+	}
+	asrt.NoError(Unmarshal([]byte(paginatedPage), &c))
+	asrt.Equal("", c.Charset)
+}
+
+const breadcrumbPage = `<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@graph": [
+	{"@type": "WebPage", "name": "Kettle"},
+	{"@type": "BreadcrumbList", "itemListElement": [
+		{"@type": "ListItem", "position": 2, "item": {"@id": "/kitchen", "name": "Kitchen"}},
+		{"@type": "ListItem", "position": 1, "name": "Home", "item": "/"},
+		{"@type": "ListItem", "position": "3", "name": "Kettles"}
+	]}
+]}</script>
+</head><body>
+<nav class="breadcrumb"><ol>
+	<li><a href="/">Home</a></li>
+	<li><a href="/kitchen">Kitchen  &amp; Dining</a></li>
+	<li aria-current="page">Kettles</li>
+</ol></nav>
+<nav class="empty"></nav>
+</body></html>`
+
+func TestHasStructuredData(t *testing.T) {
+	asrt := assert.New(t)
+
+	asrt.True(HasStructuredData([]byte(breadcrumbPage)))
+	asrt.True(HasStructuredData([]byte(`<div itemscope itemtype="https://schema.org/Person"><span itemprop="name">Jane</span></div>`)))
+	asrt.True(HasStructuredData([]byte(`<div vocab="https://schema.org/" typeof="Person"><span property="name">Jane</span></div>`)))
+	asrt.False(HasStructuredData([]byte(paginatedPage)))
+	asrt.False(HasStructuredData([]byte(`<script type="text/javascript">var ld = "ld+json"</script>`)))
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Trail   []string `goquery:"nav.breadcrumb,breadcrumbs"`
+		Empty   []string `goquery:"nav.empty,breadcrumbs"`
+		Missing []string `goquery:"nav.missing,breadcrumbs"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(breadcrumbPage), &a))
+	asrt.Equal([]string{"Home", "Kitchen & Dining", "Kettles"}, a.Trail)
+	asrt.Equal([]string{"Home", "Kitchen", "Kettles"}, a.Empty)
+	asrt.Equal([]string{"Home", "Kitchen", "Kettles"}, a.Missing)
+
+	var b struct {
+		Trail []string `goquery:"nav.empty,breadcrumbs"`
+	}
+	asrt.NoError(Unmarshal([]byte(`<nav class="empty"></nav>`), &b))
+	asrt.Empty(b.Trail)
+}
@@ -0,0 +1,56 @@
+package goq
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// rdfaProperty reports whether a property attribute lists the named property,
+// either exactly or as the local part of a prefixed term such as
+// "schema:name".
+func rdfaProperty(attr, name string) bool {
+	for _, p := range strings.Fields(attr) {
+		if p == name {
+			return true
+		}
+		if i := strings.LastIndexAny(p, ":/#"); i >= 0 && p[i+1:] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rdfaScope finds the elements with the named property that belong to the
+// items in s, leaving out those nested within another typeof item.
+func rdfaScope(s *goquery.Selection, name string) *goquery.Selection {
+	var nodes []*html.Node
+	for _, scope := range s.Nodes {
+		NodeSelector([]*html.Node{scope}).Find("[property]").Each(func(_ int, p *goquery.Selection) {
+			prop, _ := p.Attr("property")
+			if !rdfaProperty(prop, name) {
+				return
+			}
+			for par := p.Parent(); par.Length() > 0 && par.Nodes[0] != scope; par = par.Parent() {
+				if hasAttr(par, "typeof") {
+					return
+				}
+			}
+			nodes = append(nodes, p.Nodes[0])
+		})
+	}
+	return NodeSelector(nodes)
+}
+
+// rdfaVal reads the value of an RDFa property element: its content attribute,
+// else the resource it links to, else a machine-readable datetime, else its
+// text.
+func rdfaVal(d *Decoder, s *goquery.Selection) string {
+	for _, attr := range []string{"content", "resource", "href", "src", "datetime"} {
+		if val, ok := s.Attr(attr); ok {
+			return val
+		}
+	}
+	return textVal(d, s)
+}
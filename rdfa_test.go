@@ -0,0 +1,55 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rdfaPage = `<html><body>
+<div vocab="https://schema.org/" typeof="Person">
+	<span property="name">Jane Doe</span>
+	<img property="image" src="jane.jpg" alt="Jane">
+	<a property="url sameAs" href="https://jane.example.com">Home</a>
+	<meta property="schema:birthDate" content="1980-02-03">
+	<span property="telephone">555-0100</span>
+	<span property="telephone">555-0101</span>
+	<div property="worksFor" typeof="Organization">
+		<span property="name">ACME</span>
+	</div>
+</div>
+</body></html>`
+
+type rdfaOrg struct {
+	Name string `goquery:",rdfa:name"`
+}
+
+type rdfaPerson struct {
+	Name     string   `goquery:",rdfa:name"`
+	Image    string   `goquery:",rdfa:image"`
+	URL      string   `goquery:",rdfa:url"`
+	SameAs   string   `goquery:",rdfa:sameAs"`
+	Birth    string   `goquery:",rdfa:birthDate"`
+	Phones   []string `goquery:",rdfa:telephone"`
+	WorksFor rdfaOrg  `goquery:",rdfa:worksFor"`
+	Missing  string   `goquery:",rdfa:email"`
+}
+
+func TestRDFa(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Person rdfaPerson `goquery:"[typeof=Person]"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(rdfaPage), &a))
+	asrt.Equal(rdfaPerson{
+		Name:     "Jane Doe",
+		Image:    "jane.jpg",
+		URL:      "https://jane.example.com",
+		SameAs:   "https://jane.example.com",
+		Birth:    "1980-02-03",
+		Phones:   []string{"555-0100", "555-0101"},
+		WorksFor: rdfaOrg{Name: "ACME"},
+	}, a.Person)
+}
@@ -0,0 +1,82 @@
+package goq
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var reCache sync.Map
+
+// tagRegexp compiles the expression given by the `regexp:` modifier, if any.
+func (tag goqueryTag) regexp(v reflect.Value) (*regexp.Regexp, error) {
+	expr, ok := tag.modifier("regexp")
+	if !ok {
+		return nil, nil
+	}
+	if re, ok := reCache.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    err,
+			Val:    expr,
+		}
+	}
+	reCache.Store(expr, re)
+	return re, nil
+}
+
+// regexpMatch returns the first capture group of re in str, or the whole match
+// if re has no groups. It returns an empty string if re does not match.
+func regexpMatch(re *regexp.Regexp, str string) string {
+	m := re.FindStringSubmatch(str)
+	switch {
+	case m == nil:
+		return ""
+	case len(m) > 1:
+		return m[1]
+	default:
+		return m[0]
+	}
+}
+
+// unmarshalRegexp sets the fields of a struct from the named capture groups of
+// re, matching group names to field names without regard to case. Fields
+// without a matching group, or whose group did not participate in the match,
+// keep their zero values.
+func (d *Decoder) unmarshalRegexp(s *goquery.Selection, v reflect.Value, tag goqueryTag, re *regexp.Regexp) error {
+	str := tag.valFunc()(d, s)
+	m := re.FindStringSubmatch(str)
+	if m == nil {
+		return nil
+	}
+
+	t := v.Type()
+	for i, name := range re.SubexpNames() {
+		if name == "" || m[i] == "" {
+			continue
+		}
+		for j := 0; j < t.NumField(); j++ {
+			if !strings.EqualFold(t.Field(j).Name, name) || !v.Field(j).CanSet() {
+				continue
+			}
+			if err := unmarshalLiteral(m[i], v.Field(j)); err != nil {
+				return &CannotUnmarshalError{
+					V:        v,
+					Reason:   typeConversionError,
+					Err:      err,
+					Val:      m[i],
+					FldOrIdx: t.Field(j).Name,
+				}
+			}
+		}
+	}
+	return nil
+}
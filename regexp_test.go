@@ -0,0 +1,53 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const addressPage = `<html><body>
+<p class="addr">Ship to: 221 Baker, London NW1</p>
+<p class="phone" title="Call +44 20 7946 0000">Call us</p>
+</body></html>`
+
+type address struct {
+	Street   string
+	City     string
+	Postcode string
+	Country  string
+}
+
+func TestRegexpStruct(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Addr address `goquery:".addr,regexp:(?P<street>\\d+ \\w+), (?P<city>\\w+)(?: (?P<postcode>\\w+))?"`
+		None address `goquery:".phone,regexp:(?P<street>\\d+ \\w+), (?P<city>\\w+)"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(addressPage), &a))
+	asrt.Equal(address{Street: "221 Baker", City: "London", Postcode: "NW1"}, a.Addr)
+	asrt.Equal(address{}, a.None)
+}
+
+func TestRegexpScalar(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Number string `goquery:".addr,regexp:\\d+"`
+		City   string `goquery:".addr,regexp:, (\\w+)"`
+		Area   int    `goquery:".phone,[title],regexp:\\+44 (\\d+)"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(addressPage), &a))
+	asrt.Equal("221", a.Number)
+	asrt.Equal("London", a.City)
+	asrt.Equal(20, a.Area)
+
+	var b struct {
+		Bad string `goquery:".addr,regexp:(unclosed"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(addressPage), &b)).unwind()
+	asrt.Equal(invalidModifier, e.last().Reason)
+}
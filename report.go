@@ -0,0 +1,139 @@
+package goq
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// FieldSource describes how the value of a field was produced.
+type FieldSource string
+
+// The sources a DecodeReport may record for a field.
+const (
+	// SourceBuiltin values were decoded by goq itself.
+	SourceBuiltin FieldSource = "builtin"
+	// SourceUnmarshaler values were decoded by their own Unmarshaler.
+	SourceUnmarshaler FieldSource = "unmarshaler"
+)
+
+// FieldReport records how a single field was decoded.
+type FieldReport struct {
+	Source FieldSource
+
+	// Selector is the element selector of the tag the value was decoded with,
+	// which is empty for the current element itself.
+	Selector string
+	// Value is the value selector of the tag, such as "text" or "[href]".
+	Value string
+	// Attr is the attribute the value was read from, or empty if it was not
+	// read from an attribute.
+	Attr string
+	// Nodes holds the path of each element the value was decoded from, such as
+	// "/html[1]/body[1]/div[2]/a[1]", counting elements of the same name from 1.
+	Nodes []string
+}
+
+// DecodeReport records how each field was decoded. Set Decoder.Report to a
+// non-nil DecodeReport to have it filled in by Decode.
+type DecodeReport struct {
+	// Fields is keyed by the path of each decoded value in the same form as
+	// go code accessing it, such as "Items[2].Name" or "Names[\"foo\"]".
+	Fields map[string]FieldReport
+}
+
+// pathString renders the current decode path.
+func (d *Decoder) pathString() string {
+	var b strings.Builder
+	for i, elem := range d.path {
+		if i > 0 && !strings.HasPrefix(elem, "[") {
+			b.WriteByte('.')
+		}
+		b.WriteString(elem)
+	}
+	return b.String()
+}
+
+// unmarshalAt unmarshals into v as the named path element below the current
+// path, recording the result in the decoder's Report.
+func (d *Decoder) unmarshalAt(elem string, s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	if d.Report == nil {
+		return d.unmarshalByType(s, v, tag)
+	}
+
+	d.path = append(d.path, elem)
+	defer func() { d.path = d.path[:len(d.path)-1] }()
+
+	fr := FieldReport{
+		Source:   SourceBuiltin,
+		Selector: tag.selector(0),
+		Value:    tag.selector(1),
+	}
+	if u, _ := indirect(v); u != nil {
+		fr.Source = SourceUnmarshaler
+	}
+	if fr.Value == "" {
+		fr.Value = "text"
+	}
+	fr.Attr = valueAttr(fr.Value)
+	for _, n := range s.Nodes {
+		fr.Nodes = append(fr.Nodes, nodePath(n))
+	}
+	if d.Report.Fields == nil {
+		d.Report.Fields = map[string]FieldReport{}
+	}
+	d.Report.Fields[d.pathString()] = fr
+
+	return d.unmarshalByType(s, v, tag)
+}
+
+// unmarshalAtIndex unmarshals into v as the slice index or map key below the
+// current path. The path element is only formatted when a Report is set.
+func (d *Decoder) unmarshalAtIndex(key interface{}, s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	if d.Report == nil {
+		return d.unmarshalByType(s, v, tag)
+	}
+	return d.unmarshalAt(fmt.Sprintf("[%#v]", key), s, v, tag)
+}
+
+// valueAttr returns the attribute a value selector reads, if any.
+func valueAttr(val string) string {
+	switch {
+	case strings.HasPrefix(val, "[") && strings.HasSuffix(val, "]"):
+		return val[1 : len(val)-1]
+	case val == "id":
+		return "id"
+	case strings.HasPrefix(val, "selfattr:"):
+		return val[len("selfattr:"):]
+	case strings.HasPrefix(val, "flag:"):
+		return val[len("flag:"):]
+	case strings.HasPrefix(val, "dimension:"):
+		return val[len("dimension:"):]
+	}
+	return ""
+}
+
+// nodePath renders the path of an element from the root of its document, with
+// the position of each element among its siblings of the same name.
+func nodePath(n *html.Node) string {
+	var parts []string
+	for ; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		pos := 1
+		for sib := n.PrevSibling; sib != nil; sib = sib.PrevSibling {
+			if sib.Type == html.ElementNode && sib.Data == n.Data {
+				pos++
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s[%d]", n.Data, pos))
+	}
+
+	var b strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		b.WriteByte('/')
+		b.WriteString(parts[i])
+	}
+	return b.String()
+}
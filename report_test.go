@@ -0,0 +1,70 @@
+package goq
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeReportSource(t *testing.T) {
+	asrt := assert.New(t)
+
+	var p Page
+
+	d := NewDecoder(strings.NewReader(testPage))
+	d.Report = &DecodeReport{}
+	asrt.NoError(d.Decode(&p))
+	asrt.True(p.FooBar.unmarshalWasCalled)
+
+	asrt.Equal(SourceUnmarshaler, d.Report.Fields["FooBar"].Source)
+	asrt.Equal(SourceBuiltin, d.Report.Fields["Resources"].Source)
+	asrt.Equal(SourceBuiltin, d.Report.Fields["Resources[4].Name"].Source)
+	asrt.NotContains(d.Report.Fields, "Resources[5]")
+
+	var m MapTest
+	d = NewDecoder(strings.NewReader(testPage))
+	d.Report = &DecodeReport{}
+	asrt.NoError(d.Decode(&m))
+	asrt.Equal(SourceBuiltin, d.Report.Fields[`Names["foo"]`].Source)
+	asrt.Equal(SourceBuiltin, d.Report.Fields[`Nested["first"]["bar"]`].Source)
+}
+
+const provenancePage = `<html><body>
+<div class="intro">Hello</div>
+<div class="card"><h2>Anvil</h2><a href="/anvil">More</a></div>
+</body></html>`
+
+func TestDecodeReportProvenance(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Title string `goquery:".card h2"`
+		Link  string `goquery:".card a,[href]"`
+		Divs  []struct {
+			Class string `goquery:",[class]"`
+		} `goquery:"div"`
+	}
+
+	d := NewDecoder(strings.NewReader(provenancePage))
+	d.Report = &DecodeReport{}
+	asrt.NoError(d.Decode(&a))
+
+	title := d.Report.Fields["Title"]
+	asrt.Equal(".card h2", title.Selector)
+	asrt.Equal("text", title.Value)
+	asrt.Equal("", title.Attr)
+	asrt.Equal([]string{"/html[1]/body[1]/div[2]/h2[1]"}, title.Nodes)
+
+	link := d.Report.Fields["Link"]
+	asrt.Equal(".card a", link.Selector)
+	asrt.Equal("[href]", link.Value)
+	asrt.Equal("href", link.Attr)
+	asrt.Equal([]string{"/html[1]/body[1]/div[2]/a[1]"}, link.Nodes)
+
+	asrt.Equal([]string{"/html[1]/body[1]/div[1]", "/html[1]/body[1]/div[2]"}, d.Report.Fields["Divs"].Nodes)
+	class := d.Report.Fields["Divs[1].Class"]
+	asrt.Equal("", class.Selector)
+	asrt.Equal("class", class.Attr)
+	asrt.Equal([]string{"/html[1]/body[1]/div[2]"}, class.Nodes)
+}
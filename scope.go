@@ -0,0 +1,188 @@
+package goq
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// scopeSelection applies the modifiers of a field tag that move or narrow the
+// selection matched by its element selector, before it is unmarshaled.
+func (d *Decoder) scopeSelection(s *goquery.Selection, v reflect.Value, tag goqueryTag) (*goquery.Selection, error) {
+	if _, ok := tag.modifier("parent"); ok {
+		s = s.Parent()
+	}
+	if arg, ok := tag.modifier("parentsup"); ok {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			return nil, &CannotUnmarshalError{
+				V:      v,
+				Reason: invalidModifier,
+				Err:    fmt.Errorf("parentsup requires a non-negative level, got %q", arg),
+			}
+		}
+		// Climbing past the root element leaves an empty selection
+		for ; n > 0; n-- {
+			s = s.Parent()
+		}
+	}
+	if sel, ok := tag.modifier("ancestor"); ok {
+		nth := 1
+		if arg, ok := tag.modifier("nth"); ok {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 {
+				return nil, &CannotUnmarshalError{
+					V:      v,
+					Reason: invalidModifier,
+					Err:    fmt.Errorf("nth requires a positive index, got %q", arg),
+				}
+			}
+			nth = n
+		}
+		s = nthAncestor(s, sel, nth)
+	}
+	if sel, ok := tag.modifier("nextmatching"); ok {
+		s = nextMatching(s, sel)
+	}
+	if prop, ok := tag.modifier("rdfa"); ok {
+		s = rdfaScope(s, prop)
+	}
+	// indexmap keys elements by their position before any are filtered out
+	d.matched = nil
+	if _, ok := tag.modifier("indexmap"); ok {
+		d.matched = make(map[*html.Node]int, len(s.Nodes))
+		for i, n := range s.Nodes {
+			d.matched[n] = i
+		}
+	}
+	if name, ok := tag.modifier("where"); ok {
+		fn, ok := d.filters[name]
+		if !ok {
+			return nil, &CannotUnmarshalError{
+				V:      v,
+				Reason: invalidModifier,
+				Err:    fmt.Errorf("no filter registered as %q", name),
+			}
+		}
+		s = s.FilterFunction(func(_ int, e *goquery.Selection) bool {
+			return fn(e)
+		})
+	}
+	if key, ok := tag.modifier("dedupeby"); ok {
+		s = d.dedupe(s, goqueryTag(","+key).valFunc())
+	}
+	if _, ok := tag.modifier("checkedvalues"); ok {
+		s = s.FilterFunction(func(_ int, in *goquery.Selection) bool {
+			return hasAttr(in, "checked")
+		})
+	}
+	if how, ok := tag.modifier("pick"); ok {
+		picked, err := d.pick(s, how)
+		if err != nil {
+			return nil, &CannotUnmarshalError{
+				V:      v,
+				Reason: invalidModifier,
+				Err:    err,
+			}
+		}
+		s = picked
+	}
+	return s, nil
+}
+
+// nthAncestor selects, for each element of s, its nth closest ancestor
+// matching sel. Elements with fewer matching ancestors contribute nothing.
+func nthAncestor(s *goquery.Selection, sel string, nth int) *goquery.Selection {
+	res := &goquery.Selection{}
+	for i := range s.Nodes {
+		anc := s.Eq(i).ParentsFiltered(sel)
+		if anc.Length() >= nth {
+			res = res.AddSelection(anc.Eq(nth - 1))
+		}
+	}
+	return res
+}
+
+// nextMatching selects, for each element of s, its first following sibling
+// matching sel. Elements with no such sibling contribute nothing.
+func nextMatching(s *goquery.Selection, sel string) *goquery.Selection {
+	res := &goquery.Selection{}
+	for i := range s.Nodes {
+		res = res.AddSelection(s.Eq(i).NextAllFiltered(sel).First())
+	}
+	return res
+}
+
+// dedupe keeps the first of the elements sharing each key. Elements with an
+// empty key are always kept.
+func (d *Decoder) dedupe(s *goquery.Selection, key valFunc) *goquery.Selection {
+	seen := map[string]bool{}
+	return s.FilterFunction(func(_ int, e *goquery.Selection) bool {
+		k := key(d, e)
+		if k == "" {
+			return true
+		}
+		if seen[k] {
+			return false
+		}
+		seen[k] = true
+		return true
+	})
+}
+
+// pick narrows the selection to the element with the longest or shortest
+// text, counted in characters after collapsing whitespace, or with the text
+// that occurs most often. Ties go to the first in document order.
+func (d *Decoder) pick(s *goquery.Selection, how string) (*goquery.Selection, error) {
+	if how == "mode" {
+		return d.pickMode(s), nil
+	}
+	if how != "longest" && how != "shortest" {
+		return nil, fmt.Errorf("unknown pick %q", how)
+	}
+
+	best, bestLen := -1, 0
+	for i := range s.Nodes {
+		n := utf8.RuneCountInString(collapseSpace(d.text(s.Eq(i))))
+		if best < 0 || (how == "longest" && n > bestLen) || (how == "shortest" && n < bestLen) {
+			best, bestLen = i, n
+		}
+	}
+	if best < 0 {
+		return s, nil
+	}
+	return s.Eq(best), nil
+}
+
+// pickMode narrows the selection to the first element with the most common
+// text once whitespace has been collapsed.
+func (d *Decoder) pickMode(s *goquery.Selection) *goquery.Selection {
+	counts := map[string]int{}
+	first := map[string]int{}
+	best := ""
+	for i := range s.Nodes {
+		text := collapseSpace(d.text(s.Eq(i)))
+		if _, ok := first[text]; !ok {
+			first[text] = i
+		}
+		counts[text]++
+		if counts[text] > counts[best] || (counts[text] == counts[best] && first[text] < first[best]) {
+			best = text
+		}
+	}
+	if len(counts) == 0 {
+		return s
+	}
+	return s.Eq(first[best])
+}
+
+// hasAttr reports whether the first element of the selection has the named
+// attribute, whatever its value.
+func hasAttr(s *goquery.Selection, name string) bool {
+	_, ok := s.Attr(name)
+	return ok
+}
@@ -0,0 +1,190 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const scopePage = `<html><body>
+<section data-region="north">
+	<div>
+		<span class="temp">12</span>
+	</div>
+</section>
+</body></html>`
+
+func TestParentsUp(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Region  string `goquery:".temp,[data-region],parentsup:2"`
+		Wrapper string `goquery:".temp,html,parent"`
+		Self    string `goquery:".temp,parentsup:0"`
+		Past    string `goquery:".temp,text,parentsup:10"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(scopePage), &a))
+	asrt.Equal("north", a.Region)
+	asrt.Equal(`<span class="temp">12</span>`, a.Wrapper)
+	asrt.Equal("12", a.Self)
+	asrt.Equal("", a.Past)
+
+	var b struct {
+		Region string `goquery:".temp,[data-region],parentsup:two"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(scopePage), &b)).unwind()
+	asrt.Equal(invalidModifier, e.last().Reason)
+}
+
+const nestedBoxPage = `<html><body>
+<div class="box" id="outer">
+	<div class="box" id="middle">
+		<div class="box" id="inner">
+			<span class="leaf">x</span>
+		</div>
+	</div>
+</div>
+<span class="leaf">orphan</span>
+</body></html>`
+
+func TestNthAncestor(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Closest     string   `goquery:".leaf,id,ancestor:.box"`
+		Grandparent string   `goquery:".leaf,id,ancestor:.box,nth:2"`
+		Outer       string   `goquery:".leaf,id,ancestor:div.box,nth:3"`
+		TooFar      []string `goquery:".leaf,id,ancestor:.box,nth:4"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(nestedBoxPage), &a))
+	asrt.Equal("inner", a.Closest)
+	asrt.Equal("middle", a.Grandparent)
+	asrt.Equal("outer", a.Outer)
+	asrt.Empty(a.TooFar)
+
+	var b struct {
+		Bad string `goquery:".leaf,id,ancestor:.box,nth:0"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(nestedBoxPage), &b)).unwind()
+	asrt.Equal(invalidModifier, e.last().Reason)
+}
+
+const duplicatedPage = `<html><body>
+<div class="mobile">
+	<div class="item" data-id="1">One</div>
+	<div class="item" data-id="2">Two</div>
+</div>
+<div class="desktop">
+	<div class="item" data-id="1">One (desktop)</div>
+	<div class="item" data-id="3">Three</div>
+	<div class="item">Unkeyed</div>
+	<div class="item">Unkeyed</div>
+</div>
+</body></html>`
+
+func TestDedupeBy(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Items  []string `goquery:".item,dedupeby:[data-id]"`
+		ByText []string `goquery:".item,dedupeby:text"`
+		IDs    []int    `goquery:".item[data-id],[data-id],dedupeby:[data-id]"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(duplicatedPage), &a))
+	asrt.Equal([]string{"One", "Two", "Three", "Unkeyed", "Unkeyed"}, a.Items)
+	asrt.Equal([]string{"One", "Two", "One (desktop)", "Three", "Unkeyed"}, a.ByText)
+	asrt.Equal([]int{1, 2, 3}, a.IDs)
+}
+
+const boilerplatePage = `<html><body>
+<div class="content" data-i="1"><p>Subscribe!</p></div>
+<div class="content" data-i="2"><p>The  actual article, which goes on   for a while.</p></div>
+<div class="content" data-i="3"><p>Share this article now</p></div>
+<div class="content" data-i="4"><p>Sponsored</p></div>
+<div class="content" data-i="5"><p>Sponsored</p></div>
+</body></html>`
+
+func TestPick(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Longest  string `goquery:".content p,pick:longest"`
+		Shortest string `goquery:".content p,pick:shortest"`
+		Block    struct {
+			Index int    `goquery:",[data-i]"`
+			Text  string `goquery:"p"`
+		} `goquery:".content,pick:longest"`
+		Missing string `goquery:".missing,pick:longest"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(boilerplatePage), &a))
+	asrt.Equal("The  actual article, which goes on   for a while.", a.Longest)
+	asrt.Equal("Sponsored", a.Shortest)
+	asrt.Equal(2, a.Block.Index)
+	asrt.Equal(a.Longest, a.Block.Text)
+	asrt.Equal("", a.Missing)
+
+	var b struct {
+		Bad string `goquery:".content p,pick:random"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(boilerplatePage), &b))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}
+
+func TestNextMatching(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<dl class="facts">
+	<dt class="price">Price</dt>
+	<dd class="note">incl. VAT</dd>
+	<dd class="value">12.50</dd>
+	<dt class="stock">Stock</dt>
+	<dd class="value">3</dd>
+	<dt class="colour">Colour</dt>
+</dl>
+</body></html>`
+
+	var a struct {
+		Price    float64  `goquery:"dt.price,nextmatching:.value"`
+		Stock    int      `goquery:"dt.stock,nextmatching:dd"`
+		Values   []string `goquery:"dt,nextmatching:.value"`
+		Colour   string   `goquery:"dt.colour,nextmatching:dd"`
+		NextTerm string   `goquery:"dd.note,nextmatching:dt"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(12.5, a.Price)
+	asrt.Equal(3, a.Stock)
+	asrt.Equal([]string{"12.50", "3"}, a.Values)
+	asrt.Equal("", a.Colour)
+	asrt.Equal("Stock", a.NextTerm)
+}
+
+func TestPickMode(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body>
+<span class="brand">Ad</span>
+<span class="brand">ACME</span>
+<span class="brand">Globex</span>
+<span class="brand"> ACME </span>
+<span class="brand">Globex</span>
+<span class="brand">ACME</span>
+<span class="tie">b</span><span class="tie">a</span><span class="tie">a</span><span class="tie">b</span>
+</body></html>`
+
+	var a struct {
+		Brand   string `goquery:".brand,pick:mode"`
+		Tie     string `goquery:".tie,pick:mode"`
+		Missing string `goquery:".missing,pick:mode"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal("ACME", a.Brand)
+	asrt.Equal("b", a.Tie)
+	asrt.Equal("", a.Missing)
+}
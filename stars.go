@@ -0,0 +1,75 @@
+package goq
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// StarConfig describes the markup of star ratings read by the `stars` value
+// selector. Zero fields take the defaults given.
+type StarConfig struct {
+	// Filled selects the icons of filled stars, by default ".filled".
+	Filled string
+
+	// Half selects the icons of half-filled stars, which count as half a
+	// star, by default ".half".
+	Half string
+
+	// Total selects every star icon. If it matches, the number of icons is the
+	// top of the scale for ratings given as a width percentage.
+	Total string
+
+	// Max is the top of the scale for ratings given as a width percentage
+	// when Total matches nothing, by default 5.
+	Max float64
+}
+
+// starsVal reads a star rating from the first matched element. Filled and
+// half-filled star icons within it are counted if there are any, and otherwise
+// a width percentage in the inline style of the element, or of its first
+// descendant with one, is scaled to the number of stars and rounded to two
+// decimal places.
+func starsVal(d *Decoder, s *goquery.Selection) string {
+	if len(s.Nodes) == 0 {
+		return ""
+	}
+	s = s.First()
+	cfg := d.StarConfig
+	if cfg.Filled == "" {
+		cfg.Filled = ".filled"
+	}
+	if cfg.Half == "" {
+		cfg.Half = ".half"
+	}
+	if cfg.Max <= 0 {
+		cfg.Max = 5
+	}
+
+	filled, half := s.Find(cfg.Filled).Length(), s.Find(cfg.Half).Length()
+	if filled+half > 0 {
+		return strconv.FormatFloat(float64(filled)+float64(half)/2, 'f', -1, 64)
+	}
+
+	if cfg.Total != "" {
+		if n := s.Find(cfg.Total).Length(); n > 0 {
+			cfg.Max = float64(n)
+		}
+	}
+	styled := s.AddSelection(s.Find("[style]"))
+	for i := range styled.Nodes {
+		width := parseStyle(styled.Eq(i).AttrOr("style", ""))["width"]
+		if !strings.HasSuffix(width, "%") {
+			continue
+		}
+		pct, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(width, "%")), 64)
+		if err != nil {
+			continue
+		}
+		rating := math.Round(pct*cfg.Max) / 100
+		return strconv.FormatFloat(rating, 'f', -1, 64)
+	}
+	return "0"
+}
@@ -0,0 +1,62 @@
+package goq
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const starsPage = `<html><body>
+<div class="review" id="icons">
+	<i class="star filled"></i><i class="star filled"></i><i class="star filled"></i>
+	<i class="star half"></i><i class="star"></i>
+</div>
+<div class="review" id="width">
+	<div class="stars-outer"><div class="stars-inner" style="color: gold; width: 93%"></div></div>
+</div>
+<div class="review" id="self" style="width:40%"></div>
+<div class="review" id="custom">
+	<span class="icon on"></span><span class="icon on"></span><span class="icon"></span>
+</div>
+<div class="review" id="none"></div>
+</body></html>`
+
+func TestStarsIcons(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Icons  float64 `goquery:"#icons,stars"`
+		Custom float64 `goquery:"#custom,stars"`
+		None   float64 `goquery:"#none,stars"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(starsPage), &a))
+	asrt.Equal(3.5, a.Icons)
+	asrt.Zero(a.Custom)
+	asrt.Zero(a.None)
+
+	d := NewDecoder(strings.NewReader(starsPage))
+	d.StarConfig = StarConfig{Filled: ".icon.on"}
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal(2.0, a.Custom)
+}
+
+func TestStarsWidth(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Width float64 `goquery:"#width,stars"`
+		Self  float64 `goquery:"#self,stars"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(starsPage), &a))
+	asrt.Equal(4.65, a.Width)
+	asrt.Equal(2.0, a.Self)
+
+	d := NewDecoder(strings.NewReader(starsPage))
+	d.StarConfig = StarConfig{Max: 10}
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal(9.3, a.Width)
+	asrt.Equal(4.0, a.Self)
+}
@@ -0,0 +1,129 @@
+package goq
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// streamSelector matches the selector forms supported by Stream: a tag name, a
+// single class, or a tag name with a single class.
+var streamSelector = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9-]*)?(\.[a-zA-Z_][a-zA-Z0-9_-]*)?$`)
+
+// voidElements never have children or an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+type streamMatcher struct {
+	tag, class string
+}
+
+func newStreamMatcher(sel string) (streamMatcher, error) {
+	m := streamSelector.FindStringSubmatch(sel)
+	if sel == "" || m == nil {
+		return streamMatcher{}, fmt.Errorf("unsupported stream selector %q: only tag, .class and tag.class are supported", sel)
+	}
+	return streamMatcher{tag: strings.ToLower(m[1]), class: strings.TrimPrefix(m[2], ".")}, nil
+}
+
+func (m streamMatcher) match(t html.Token) bool {
+	if m.tag != "" && t.Data != m.tag {
+		return false
+	}
+	if m.class == "" {
+		return true
+	}
+	for _, a := range t.Attr {
+		if a.Key == "class" {
+			for _, c := range strings.Fields(a.Val) {
+				if c == m.class {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Stream tokenizes the HTML read from r without building a document, calling
+// fn with the subtree of each element matching selector. This trades the
+// generality of the Decoder for memory use on very large documents. Only the
+// selector forms `tag`, `.class` and `tag.class` are supported, and elements
+// nested within a match are part of that match rather than reported
+// separately. The selection passed to fn may be unmarshaled with
+// UnmarshalSelection. Any error returned by fn stops the stream and is
+// returned.
+//
+// Subtrees are built directly from tokens, so the implied end tags of the full
+// HTML parsing algorithm are only approximated: an end tag closes the nearest
+// open element of the same name.
+func Stream(r io.Reader, selector string, fn func(*goquery.Selection) error) error {
+	m, err := newStreamMatcher(selector)
+	if err != nil {
+		return err
+	}
+
+	z := html.NewTokenizer(r)
+	var stack []*html.Node
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return nil
+			}
+			return z.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if len(stack) == 0 && !m.match(tok) {
+				continue
+			}
+			n := &html.Node{Type: html.ElementNode, Data: tok.Data, DataAtom: tok.DataAtom, Attr: tok.Attr}
+			if len(stack) > 0 {
+				stack[len(stack)-1].AppendChild(n)
+			}
+			if tt == html.StartTagToken && !voidElements[tok.Data] {
+				stack = append(stack, n)
+			} else if len(stack) == 0 {
+				if err := fn(NodeSelector([]*html.Node{n})); err != nil {
+					return err
+				}
+			}
+		case html.EndTagToken:
+			if len(stack) == 0 {
+				continue
+			}
+			tok := z.Token()
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].Data == tok.Data {
+					root := stack[0]
+					stack = stack[:i]
+					if len(stack) == 0 {
+						if err := fn(NodeSelector([]*html.Node{root})); err != nil {
+							return err
+						}
+					}
+					break
+				}
+			}
+		case html.TextToken, html.CommentToken:
+			if len(stack) == 0 {
+				continue
+			}
+			tok := z.Token()
+			typ := html.TextNode
+			if tt == html.CommentToken {
+				typ = html.CommentNode
+			}
+			stack[len(stack)-1].AppendChild(&html.Node{Type: typ, Data: tok.Data})
+		}
+	}
+}
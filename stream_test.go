@@ -0,0 +1,110 @@
+package goq
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+type streamItem struct {
+	ID    int    `goquery:",[data-id]"`
+	Name  string `goquery:".name"`
+	Price int    `goquery:".price"`
+}
+
+func largeDocument(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<html><body><div id=\"items\">")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, `<div class="item card" data-id="%d"><span class="name">Item %d</span><br><span class="price">%d</span><div class="item">nested</div></div>`, i, i, i*10)
+		buf.WriteString("<p>filler</p>")
+	}
+	buf.WriteString("</div></body></html>")
+	return buf.Bytes()
+}
+
+func TestStream(t *testing.T) {
+	asrt := assert.New(t)
+
+	doc := largeDocument(5000)
+
+	var items []streamItem
+	err := Stream(bytes.NewReader(doc), "div.item", func(s *goquery.Selection) error {
+		var it streamItem
+		if err := UnmarshalSelection(s, &it); err != nil {
+			return err
+		}
+		items = append(items, it)
+		return nil
+	})
+
+	asrt.NoError(err)
+	asrt.Len(items, 5000)
+	asrt.Equal(streamItem{ID: 4321, Name: "Item 4321", Price: 43210}, items[4321])
+}
+
+func TestStreamSelectors(t *testing.T) {
+	asrt := assert.New(t)
+
+	count := func(sel string) (int, error) {
+		var n int
+		err := Stream(bytes.NewReader(largeDocument(10)), sel, func(*goquery.Selection) error {
+			n++
+			return nil
+		})
+		return n, err
+	}
+
+	n, err := count("p")
+	asrt.NoError(err)
+	asrt.Equal(10, n)
+
+	n, err = count(".card")
+	asrt.NoError(err)
+	asrt.Equal(10, n)
+
+	n, err = count("br")
+	asrt.NoError(err)
+	asrt.Equal(10, n)
+
+	for _, sel := range []string{"", "#items", "div .item", "div.item.card", "div > p", "a[href]"} {
+		_, err = count(sel)
+		asrt.Error(err, sel)
+	}
+}
+
+func TestStreamCallbackError(t *testing.T) {
+	asrt := assert.New(t)
+
+	stop := fmt.Errorf("stop")
+	var n int
+	err := Stream(bytes.NewReader(largeDocument(10)), "p", func(*goquery.Selection) error {
+		n++
+		return stop
+	})
+	asrt.Equal(stop, err)
+	asrt.Equal(1, n)
+}
+
+func BenchmarkStream(b *testing.B) {
+	doc := largeDocument(10000)
+	for i := 0; i < b.N; i++ {
+		Stream(bytes.NewReader(doc), "div.item", func(s *goquery.Selection) error {
+			var it streamItem
+			return UnmarshalSelection(s, &it)
+		})
+	}
+}
+
+func BenchmarkStreamDocument(b *testing.B) {
+	doc := largeDocument(10000)
+	for i := 0; i < b.N; i++ {
+		var items struct {
+			Items []streamItem `goquery:"#items > div.item"`
+		}
+		Unmarshal(doc, &items)
+	}
+}
@@ -0,0 +1,129 @@
+package goq
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// parseStyle splits an inline style attribute into its declarations. Property
+// names are lower-cased except for custom properties, which are case
+// sensitive. Semicolons within quotes or parentheses do not end a declaration.
+func parseStyle(style string) map[string]string {
+	decls := map[string]string{}
+
+	var depth int
+	var quote rune
+	start := 0
+	add := func(decl string) {
+		kv := strings.SplitN(decl, ":", 2)
+		if len(kv) != 2 {
+			return
+		}
+		prop := strings.TrimSpace(kv[0])
+		if !strings.HasPrefix(prop, "--") {
+			prop = strings.ToLower(prop)
+		}
+		if prop != "" {
+			decls[prop] = strings.TrimSpace(kv[1])
+		}
+	}
+
+	for i, r := range style {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == ';' && depth == 0:
+			add(style[start:i])
+			start = i + 1
+		}
+	}
+	add(style[start:])
+
+	return decls
+}
+
+// isHidden reports whether an element is hidden from view, either itself or
+// through an ancestor, by the hidden attribute, aria-hidden="true", an inline
+// style of display: none or visibility: hidden, or being a hidden input.
+func isHidden(n *html.Node) bool {
+	for ; n != nil; n = n.Parent {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		s := NodeSelector([]*html.Node{n})
+		if hasAttr(s, "hidden") || strings.EqualFold(s.AttrOr("aria-hidden", ""), "true") {
+			return true
+		}
+		if n.Data == "input" && strings.EqualFold(s.AttrOr("type", ""), "hidden") {
+			return true
+		}
+		decls := parseStyle(s.AttrOr("style", ""))
+		if strings.HasPrefix(strings.ToLower(decls["display"]), "none") ||
+			strings.HasPrefix(strings.ToLower(decls["visibility"]), "hidden") {
+			return true
+		}
+	}
+	return false
+}
+
+// preferVisible drops the hidden elements from a selection of several, or
+// leaves it as is if none or all of them are hidden.
+func preferVisible(s *goquery.Selection) *goquery.Selection {
+	if len(s.Nodes) < 2 {
+		return s
+	}
+	visible := s.FilterFunction(func(_ int, e *goquery.Selection) bool {
+		return !isHidden(e.Nodes[0])
+	})
+	if visible.Length() == 0 {
+		return s
+	}
+	return visible
+}
+
+// styleVal returns the value of a property in the element's style attribute.
+func styleVal(prop string) valFunc {
+	if !strings.HasPrefix(prop, "--") {
+		prop = strings.ToLower(prop)
+	}
+	return func(_ *Decoder, s *goquery.Selection) string {
+		style, _ := s.Attr("style")
+		return parseStyle(style)[prop]
+	}
+}
+
+// cssVarVal returns the value of a custom property in the element's style
+// attribute. The leading dashes of the property name are optional.
+func cssVarVal(name string) valFunc {
+	return styleVal("--" + strings.TrimPrefix(name, "--"))
+}
+
+var cssURL = regexp.MustCompile(`url\(\s*(?:'([^']*)'|"([^"]*)"|([^)'"]*?))\s*\)`)
+
+// bgImageVal returns the URL of the background image set by the element's
+// inline style, resolved against the decoder's BaseURL.
+func bgImageVal(d *Decoder, s *goquery.Selection) string {
+	style, _ := s.Attr("style")
+	decls := parseStyle(style)
+	bg, ok := decls["background-image"]
+	if !ok {
+		bg = decls["background"]
+	}
+
+	m := cssURL.FindStringSubmatch(bg)
+	if m == nil {
+		return ""
+	}
+	return d.resolveURL(m[1] + m[2] + m[3])
+}
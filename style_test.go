@@ -0,0 +1,43 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const stylePage = `<html><body>
+<div class="bar" style="--progress: 42; --Label:'a;b'; COLOR: red; background: url(data:image/png;base64,AA==)">
+</div>
+</body></html>`
+
+func TestParseStyle(t *testing.T) {
+	asrt := assert.New(t)
+
+	asrt.Equal(map[string]string{
+		"color":      "blue",
+		"margin-top": "0",
+		"--Accent":   "#fff",
+	}, parseStyle(" color : blue;margin-top:0;; --Accent: #fff ;junk"))
+}
+
+func TestStyleValues(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Progress   int    `goquery:".bar,cssvar:--progress"`
+		NoDashes   int    `goquery:".bar,cssvar:progress"`
+		Label      string `goquery:".bar,cssvar:--Label"`
+		Color      string `goquery:".bar,style:color"`
+		Background string `goquery:".bar,style:background"`
+		Missing    string `goquery:".bar,cssvar:--missing"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(stylePage), &a))
+	asrt.Equal(42, a.Progress)
+	asrt.Equal(42, a.NoDashes)
+	asrt.Equal("'a;b'", a.Label)
+	asrt.Equal("red", a.Color)
+	asrt.Equal("url(data:image/png;base64,AA==)", a.Background)
+	asrt.Equal("", a.Missing)
+}
@@ -0,0 +1,282 @@
+package goq
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// tableRows returns the rows of the matched tables, or the matched elements
+// themselves if they are rows.
+func tableRows(s *goquery.Selection) *goquery.Selection {
+	rows := s.Filter("tr")
+	return rows.AddSelection(s.Not("tr").Find("tr"))
+}
+
+// rowCells returns the header and data cells of a row in order.
+func rowCells(row *goquery.Selection) *goquery.Selection {
+	return row.ChildrenFiltered("td, th")
+}
+
+// unmarshalGrid builds a map from a two column table, keyed by the text of
+// the first cell of each row with the second cell as the value. Rows with
+// fewer than two cells or an empty first cell are skipped.
+func (d *Decoder) unmarshalGrid(s *goquery.Selection, v reflect.Value) error {
+	var err error
+	tableRows(s).EachWithBreak(func(_ int, row *goquery.Selection) bool {
+		cells := rowCells(row)
+		if cells.Length() < 2 {
+			return true
+		}
+		key := textVal(d, cells.Eq(0))
+		if key == "" {
+			return true
+		}
+		err = d.setMapEntry(v, key, cells.Eq(1))
+		return err == nil
+	})
+	return err
+}
+
+// unmarshalTHTD builds a map from table rows keyed by the text of the `th` of
+// each row. A slice value collects every `td` of the row and any other value
+// uses the first. Rows without a `th`, with an empty one, or without a `td`
+// are skipped.
+func (d *Decoder) unmarshalTHTD(s *goquery.Selection, v reflect.Value) error {
+	multi := TypeDeref(v.Type().Elem()).Kind() == reflect.Slice
+
+	var err error
+	tableRows(s).EachWithBreak(func(_ int, row *goquery.Selection) bool {
+		key := textVal(d, row.ChildrenFiltered("th").First())
+		tds := row.ChildrenFiltered("td")
+		if key == "" || tds.Length() == 0 {
+			return true
+		}
+		if !multi {
+			tds = tds.First()
+		}
+		err = d.setMapEntry(v, key, tds)
+		return err == nil
+	})
+	return err
+}
+
+// defaultHeaderSep joins the header cells above a column into its key.
+const defaultHeaderSep = " / "
+
+// headerRows returns the header rows of a table: those in its `thead`, or else
+// the leading rows made only of `th` cells.
+func headerRows(rows *goquery.Selection) *goquery.Selection {
+	head := rows.FilterFunction(func(_ int, row *goquery.Selection) bool {
+		return row.ParentsFiltered("thead").Length() > 0
+	})
+	if head.Length() > 0 {
+		return head
+	}
+	n := 0
+	for ; n < rows.Length(); n++ {
+		cells := rowCells(rows.Eq(n))
+		if cells.Length() == 0 || cells.Length() != cells.Filter("th").Length() {
+			break
+		}
+	}
+	return rows.Slice(0, n)
+}
+
+// spanAttr reads a colspan or rowspan attribute, which is at least 1.
+func spanAttr(cell *goquery.Selection, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(cell.AttrOr(name, "1")))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// expandRows returns the cells of each row by column. A cell spanning several
+// columns is repeated for each of them, and one spanning several rows is
+// repeated in each of the following rows it covers, moving their own cells
+// to the right. Spans do not carry over from one table to the next.
+func expandRows(rows *goquery.Selection) [][]*goquery.Selection {
+	type span struct {
+		cell *goquery.Selection
+		rows int
+	}
+	var pending map[int]*span
+	var table *html.Node
+
+	grid := make([][]*goquery.Selection, rows.Length())
+	for r := range rows.Nodes {
+		row := rows.Eq(r)
+		var t *html.Node
+		if closest := row.Closest("table"); closest.Length() > 0 {
+			t = closest.Nodes[0]
+		}
+		if pending == nil || t != table {
+			pending, table = map[int]*span{}, t
+		}
+
+		var cols []*goquery.Selection
+		carry := func() {
+			for sp := pending[len(cols)]; sp != nil && sp.rows > 0; sp = pending[len(cols)] {
+				sp.rows--
+				cols = append(cols, sp.cell)
+			}
+		}
+		rowCells(row).Each(func(_ int, cell *goquery.Selection) {
+			carry()
+			rowspan := spanAttr(cell, "rowspan")
+			for n := spanAttr(cell, "colspan"); n > 0; n-- {
+				pending[len(cols)] = &span{cell, rowspan - 1}
+				cols = append(cols, cell)
+			}
+		})
+		carry()
+		grid[r] = cols
+	}
+	return grid
+}
+
+// headerKeys gives the key of each column of a table from its header rows,
+// joining the text of the header cells above the column with sep. A cell
+// spanning several columns is part of each of their keys, and one spanning
+// several rows appears in the key once.
+func (d *Decoder) headerKeys(head *goquery.Selection, sep string) []string {
+	var parts [][]string
+	var last []*html.Node
+	pending := map[int]int{}
+
+	head.Each(func(_ int, row *goquery.Selection) {
+		col := 0
+		skip := func() {
+			for pending[col] > 0 {
+				pending[col]--
+				col++
+			}
+		}
+		rowCells(row).Each(func(_ int, cell *goquery.Selection) {
+			skip()
+			text := textVal(d, cell)
+			rows := spanAttr(cell, "rowspan")
+			for n := spanAttr(cell, "colspan"); n > 0; n-- {
+				for len(parts) <= col {
+					parts = append(parts, nil)
+					last = append(last, nil)
+				}
+				if text != "" && last[col] != cell.Nodes[0] {
+					parts[col] = append(parts[col], text)
+				}
+				last[col] = cell.Nodes[0]
+				pending[col] = rows - 1
+				col++
+			}
+		})
+		skip()
+	})
+
+	keys := make([]string, len(parts))
+	for i, p := range parts {
+		keys[i] = strings.Join(p, sep)
+	}
+	return keys
+}
+
+// unmarshalHeaders fills the slice of maps v with one map for each body row
+// of the matched tables, keyed by the header of each column.
+func (d *Decoder) unmarshalHeaders(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	sep := defaultHeaderSep
+	if arg, ok := tag.modifier("headersep"); ok {
+		sep = arg
+	}
+
+	slice := v
+	eleT := v.Type().Elem()
+
+	for t := range s.Nodes {
+		rows := tableRows(s.Eq(t))
+		head := headerRows(rows)
+		keys := d.headerKeys(head, sep)
+
+		var err error
+		body := rows.NotSelection(head)
+		grid := expandRows(body)
+		body.EachWithBreak(func(r int, _ *goquery.Selection) bool {
+			cells := grid[r]
+			if len(cells) == 0 {
+				return true
+			}
+
+			m := reflect.New(TypeDeref(eleT))
+			m.Elem().Set(reflect.MakeMap(m.Elem().Type()))
+			for i, cell := range cells {
+				if i >= len(keys) || keys[i] == "" {
+					continue
+				}
+				if err = d.setMapEntry(m.Elem(), keys[i], cell); err != nil {
+					return false
+				}
+			}
+
+			if eleT.Kind() != reflect.Ptr {
+				m = m.Elem()
+			}
+			v = reflect.Append(v, m)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	slice.Set(v)
+	return nil
+}
+
+// unmarshalTable appends a slice to the slice of slices v for each row of the
+// matched tables, holding the text of its cells, whether header or data cells.
+// A cell spanning several columns or rows is repeated in each of them.
+func (d *Decoder) unmarshalTable(s *goquery.Selection, v reflect.Value) error {
+	slice := v
+	eleT := v.Type().Elem()
+
+	for i, cells := range expandRows(tableRows(s)) {
+		var strs []string
+		for _, cell := range cells {
+			strs = append(strs, textVal(d, cell))
+		}
+		newV := reflect.New(TypeDeref(eleT))
+		newV.Elem().Set(reflect.MakeSlice(TypeDeref(eleT), 0, len(strs)))
+		if err := unmarshalStrings(strs, newV.Elem()); err != nil {
+			return &CannotUnmarshalError{
+				Reason:   typeConversionError,
+				Err:      err,
+				V:        v,
+				FldOrIdx: i,
+			}
+		}
+		if eleT.Kind() != reflect.Ptr {
+			newV = newV.Elem()
+		}
+		v = reflect.Append(v, newV)
+	}
+
+	slice.Set(v)
+	return nil
+}
+
+// unmarshalRowTotal sets the numeric v to the sum of the numbers in the cells
+// of the matched row that match sel, ignoring thousands separators. Cells that
+// are not numeric, such as labels, are skipped.
+func (d *Decoder) unmarshalRowTotal(s *goquery.Selection, v reflect.Value, sel string) error {
+	var total float64
+	cells := s.Find(sel)
+	for i := range cells.Nodes {
+		str := strings.ReplaceAll(textVal(d, cells.Eq(i)), ",", "")
+		if f, err := strconv.ParseFloat(str, 64); err == nil {
+			total += f
+		}
+	}
+	return setNumber(total, v)
+}
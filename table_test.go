@@ -0,0 +1,222 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gridPage = `<html><body>
+<table class="specs">
+	<tr><th colspan="2">Specifications</th></tr>
+	<tr><th>Weight</th><td>12</td></tr>
+	<tr><td>Colour</td><td>Red</td></tr>
+	<tr><td></td><td>Nameless</td></tr>
+	<tr><td>Width</td><td>30</td><td>cm</td></tr>
+</table>
+</body></html>`
+
+func TestGrid(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Specs map[string]string `goquery:"table.specs,grid"`
+		Rows  map[string]string `goquery:"table.specs tr,grid"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(gridPage), &a))
+	want := map[string]string{"Weight": "12", "Colour": "Red", "Width": "30"}
+	asrt.Equal(want, a.Specs)
+	asrt.Equal(want, a.Rows)
+}
+
+const stripedPage = `<html><body>
+<table id="first">
+	<tr><td>1</td></tr>
+	<tr><td>2</td></tr>
+	<tr><td>3</td></tr>
+	<tr><td>4</td></tr>
+	<tr><td>5</td></tr>
+</table>
+<table id="second">
+	<tr><td>a</td></tr>
+	<tr><td>b</td></tr>
+</table>
+</body></html>`
+
+func TestNthChildWithinScope(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Tables []struct {
+			Odd  []string `goquery:"tr:nth-child(odd)"`
+			Even []string `goquery:"tr:nth-child(even)"`
+		} `goquery:"table"`
+		First struct {
+			Odd []string `goquery:"tr:nth-child(odd) td"`
+		} `goquery:"#first"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(stripedPage), &a))
+	asrt.Len(a.Tables, 2)
+	asrt.Equal([]string{"1", "3", "5"}, a.Tables[0].Odd)
+	asrt.Equal([]string{"2", "4"}, a.Tables[0].Even)
+	asrt.Equal([]string{"a"}, a.Tables[1].Odd)
+	asrt.Equal([]string{"b"}, a.Tables[1].Even)
+	asrt.Equal([]string{"1", "3", "5"}, a.First.Odd)
+}
+
+const thtdPage = `<html><body>
+<table class="spec">
+	<thead><tr><td colspan="2">Anvil</td></tr></thead>
+	<tr><th>Weight</th><td>12 kg</td></tr>
+	<tr><th>Colours</th><td>Black</td><td>Red</td></tr>
+	<tr><td>No header</td><td>ignored</td></tr>
+	<tr><th>Nothing</th></tr>
+	<tr><th> </th><td>blank key</td></tr>
+</table>
+</body></html>`
+
+func TestTHTD(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Spec  map[string]string   `goquery:"table.spec,thtd"`
+		Multi map[string][]string `goquery:"table.spec,thtd"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(thtdPage), &a))
+	asrt.Equal(map[string]string{"Weight": "12 kg", "Colours": "Black"}, a.Spec)
+	asrt.Equal(map[string][]string{"Weight": {"12 kg"}, "Colours": {"Black", "Red"}}, a.Multi)
+}
+
+const financePage = `<html><body>
+<table class="results">
+	<thead>
+		<tr><th rowspan="2">Region</th><th colspan="2">Revenue</th><th colspan="2">Profit</th></tr>
+		<tr><th>Q1</th><th>Q2</th><th>Q1</th><th>Q2</th></tr>
+	</thead>
+	<tbody>
+		<tr><td>North</td><td>10</td><td>12</td><td>2</td><td>3</td></tr>
+		<tr><td>South</td><td colspan="2">8</td><td>1</td><td>1</td></tr>
+	</tbody>
+</table>
+<table class="simple">
+	<tr><th>Name</th><th>Age</th></tr>
+	<tr><td>Alice</td><td>30</td></tr>
+	<tr><td>Bob</td><td>25</td><td>extra</td></tr>
+</table>
+</body></html>`
+
+func TestHeaders(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Results []map[string]string `goquery:"table.results,headers"`
+		Dashed  []map[string]string `goquery:"table.results,headers,headersep:-"`
+		Simple  []map[string]string `goquery:"table.simple,headers"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(financePage), &a))
+	asrt.Equal([]map[string]string{
+		{"Region": "North", "Revenue / Q1": "10", "Revenue / Q2": "12", "Profit / Q1": "2", "Profit / Q2": "3"},
+		{"Region": "South", "Revenue / Q1": "8", "Revenue / Q2": "8", "Profit / Q1": "1", "Profit / Q2": "1"},
+	}, a.Results)
+	asrt.Len(a.Dashed, 2)
+	asrt.Equal("12", a.Dashed[0]["Revenue-Q2"])
+	asrt.Equal([]map[string]string{
+		{"Name": "Alice", "Age": "30"},
+		{"Name": "Bob", "Age": "25"},
+	}, a.Simple)
+}
+
+const ledgerPage = `<html><body>
+<table>
+	<tr><th>Account</th><th>Jan</th><th>Feb</th><th>Note</th></tr>
+	<tr class="row"><td>Rent</td><td class="amt">1,200</td><td class="amt">1,200.50</td><td>n/a</td></tr>
+	<tr class="row"><td>Power</td><td class="amt">80</td><td class="amt">95.25</td><td>7</td></tr>
+	<tr class="row"><td>Empty</td></tr>
+</table>
+</body></html>`
+
+func TestRowTotal(t *testing.T) {
+	asrt := assert.New(t)
+
+	type row struct {
+		Account string  `goquery:"td:first-child"`
+		Total   float64 `goquery:",rowtotal"`
+		Amounts int     `goquery:",rowtotal,cells:td.amt"`
+	}
+	var a struct {
+		Rows []row `goquery:"tr.row"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(ledgerPage), &a))
+	asrt.Equal([]row{
+		{Account: "Rent", Total: 2400.5, Amounts: 2400},
+		{Account: "Power", Total: 182.25, Amounts: 175},
+		{Account: "Empty"},
+	}, a.Rows)
+}
+
+func TestTable(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Results [][]string `goquery:"table.results,table"`
+		Simple  [][]string `goquery:"table.simple,table"`
+		Missing [][]string `goquery:"table.missing,table"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(financePage), &a))
+	asrt.Equal([][]string{
+		{"Region", "Revenue", "Revenue", "Profit", "Profit"},
+		{"Region", "Q1", "Q2", "Q1", "Q2"},
+		{"North", "10", "12", "2", "3"},
+		{"South", "8", "8", "1", "1"},
+	}, a.Results)
+	asrt.Equal([][]string{
+		{"Name", "Age"},
+		{"Alice", "30"},
+		{"Bob", "25", "extra"},
+	}, a.Simple)
+	asrt.Empty(a.Missing)
+}
+
+const regionPage = `<html><body>
+<table>
+	<thead><tr><th>Region</th><th>City</th><th>Sales</th></tr></thead>
+	<tbody>
+		<tr><td rowspan="2">North</td><td>Leeds</td><td>10</td></tr>
+		<tr><td>York</td><td>7</td></tr>
+		<tr><td>South</td><td colspan="2" rowspan="2">n/a</td></tr>
+		<tr><td>West</td></tr>
+	</tbody>
+</table>
+<table><tr><td>Next</td><td>table</td></tr></table>
+</body></html>`
+
+func TestRowSpans(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Rows  []map[string]string `goquery:"table:first-of-type,headers"`
+		Table [][]string          `goquery:"table,table"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(regionPage), &a))
+	asrt.Equal([]map[string]string{
+		{"Region": "North", "City": "Leeds", "Sales": "10"},
+		{"Region": "North", "City": "York", "Sales": "7"},
+		{"Region": "South", "City": "n/a", "Sales": "n/a"},
+		{"Region": "West", "City": "n/a", "Sales": "n/a"},
+	}, a.Rows)
+	asrt.Equal([][]string{
+		{"Region", "City", "Sales"},
+		{"North", "Leeds", "10"},
+		{"North", "York", "7"},
+		{"South", "n/a", "n/a"},
+		{"West", "n/a", "n/a"},
+		{"Next", "table"},
+	}, a.Table)
+}
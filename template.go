@@ -0,0 +1,42 @@
+package goq
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// unmarshalTemplate executes the template registered as name with the
+// selection as its data and unmarshals the output into v.
+func (d *Decoder) unmarshalTemplate(s *goquery.Selection, v reflect.Value, name string) error {
+	t, ok := d.templates[name]
+	if !ok {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    fmt.Errorf("no template registered as %q", name),
+		}
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, s); err != nil {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: customUnmarshalError,
+			Err:    err,
+		}
+	}
+
+	str := b.String()
+	if err := unmarshalLiteral(str, v); err != nil {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: typeConversionError,
+			Err:    err,
+			Val:    str,
+		}
+	}
+	return nil
+}
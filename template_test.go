@@ -0,0 +1,59 @@
+package goq
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const authorPage = `<html><body>
+<div class="author">
+	<span class="first">Jane</span>
+	<span class="last">Doe</span>
+	<a href="/jane">Profile</a>
+</div>
+</body></html>`
+
+func TestTemplate(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Name  string `goquery:".author,tmpl:name"`
+		Link  string `goquery:".author a,tmpl:link"`
+		Spans int    `goquery:".author,tmpl:spans"`
+	}
+
+	d := NewDecoder(strings.NewReader(authorPage))
+	d.RegisterTemplate("name", template.Must(template.New("name").Parse(
+		`{{(.Find ".last").Text}}, {{(.Find ".first").Text}}`)))
+	d.RegisterTemplate("link", template.Must(template.New("link").Parse(
+		`{{.Text}} <{{.AttrOr "href" ""}}>`)))
+	d.RegisterTemplate("spans", template.Must(template.New("spans").Parse(
+		`{{(.Find "span").Length}}`)))
+
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal("Doe, Jane", a.Name)
+	asrt.Equal("Profile </jane>", a.Link)
+	asrt.Equal(2, a.Spans)
+}
+
+func TestTemplateErrors(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Name string `goquery:".author,tmpl:missing"`
+	}
+	err := NewDecoder(strings.NewReader(authorPage)).Decode(&a)
+	e := checkErr(asrt, err)
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+
+	var b struct {
+		Name string `goquery:".author,tmpl:bad"`
+	}
+	d := NewDecoder(strings.NewReader(authorPage))
+	d.RegisterTemplate("bad", template.Must(template.New("bad").Parse(`{{.Nope}}`)))
+	e = checkErr(asrt, d.Decode(&b))
+	asrt.Equal(customUnmarshalError, e.unwind().last().Reason)
+}
@@ -0,0 +1,245 @@
+package goq
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// timeLayouts are tried in order when a time.Time field has no `layout:`
+// modifier.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// relativeUnits approximates each unit of a relative time, with months of 30
+// days and years of 365.
+var relativeUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second,
+	"second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute,
+	"minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour,
+	"hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "wks": 7 * 24 * time.Hour,
+	"week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+	"mo": 30 * 24 * time.Hour, "month": 30 * 24 * time.Hour, "months": 30 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour, "yr": 365 * 24 * time.Hour, "yrs": 365 * 24 * time.Hour,
+	"year": 365 * 24 * time.Hour, "years": 365 * 24 * time.Hour,
+}
+
+var relativeTime = regexp.MustCompile(`^(in )?(\d+|an?|one) ?([a-z]+)( ago)?$`)
+
+// parseRelative parses a phrase such as "3 days ago", "2h ago" or "in a week"
+// into how long ago it is, which is negative for times in the future.
+func parseRelative(str string) (time.Duration, error) {
+	str = strings.ToLower(collapseSpace(str))
+	switch str {
+	case "now", "just now":
+		return 0, nil
+	case "yesterday":
+		return 24 * time.Hour, nil
+	case "tomorrow":
+		return -24 * time.Hour, nil
+	}
+
+	m := relativeTime.FindStringSubmatch(str)
+	if m == nil || (m[1] == "") == (m[4] == "") {
+		return 0, fmt.Errorf("%q is not a relative time", str)
+	}
+	unit, ok := relativeUnits[m[3]]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q in relative time %q", m[3], str)
+	}
+
+	n := 1
+	if m[2][0] >= '0' && m[2][0] <= '9' {
+		n, _ = strconv.Atoi(m[2])
+	}
+	ago := time.Duration(n) * unit
+	if m[1] != "" {
+		ago = -ago
+	}
+	return ago, nil
+}
+
+// now returns the time relative times are counted from.
+func (d *Decoder) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
+
+// unmarshalRelative parses the value of the selection as a relative time into
+// either a time.Time counted back from the decoder's Now, or a time.Duration
+// of how long ago it was. An empty value leaves v untouched.
+func (d *Decoder) unmarshalRelative(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	isTime := v.Type() == reflect.TypeOf(time.Time{})
+	if !isTime && v.Type() != reflect.TypeOf(time.Duration(0)) {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    fmt.Errorf("relativetime needs a time.Time or time.Duration, not %s", v.Type()),
+		}
+	}
+
+	str, err := d.transformValue(tag.valFunc()(d, s), v, tag)
+	if err != nil {
+		return err
+	}
+	if str == "" {
+		return nil
+	}
+
+	ago, err := parseRelative(str)
+	if err != nil {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: typeConversionError,
+			Err:    err,
+			Val:    str,
+		}
+	}
+
+	if isTime {
+		v.Set(reflect.ValueOf(d.now().Add(-ago)))
+	} else {
+		v.SetInt(int64(ago))
+	}
+	return nil
+}
+
+// DefaultWordsPerMinute is the reading speed used by `readingtime` when
+// Decoder.WordsPerMinute is not set.
+const DefaultWordsPerMinute = 200
+
+// unmarshalReadingTime sets the time.Duration v to an estimate of how long the
+// value of the selection takes to read, rounded to the second.
+func (d *Decoder) unmarshalReadingTime(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	if v.Type() != reflect.TypeOf(time.Duration(0)) {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: invalidModifier,
+			Err:    fmt.Errorf("readingtime needs a time.Duration, not %s", v.Type()),
+		}
+	}
+
+	wpm := d.WordsPerMinute
+	if wpm <= 0 {
+		wpm = DefaultWordsPerMinute
+	}
+	words := len(strings.Fields(tag.valFunc()(d, s)))
+	dur := time.Duration(words) * time.Minute / time.Duration(wpm)
+	v.SetInt(int64(dur.Round(time.Second)))
+	return nil
+}
+
+// location returns the location naive times are interpreted in.
+func (d *Decoder) location() *time.Location {
+	if d.DefaultLocation != nil {
+		return d.DefaultLocation
+	}
+	return time.UTC
+}
+
+// parseTime parses str using the layout given by the tag, or else the first of
+// timeLayouts that matches.
+func (d *Decoder) parseTime(str string, tag goqueryTag) (time.Time, error) {
+	if layout, ok := tag.modifier("layout"); ok {
+		return time.ParseInLocation(layout, str, d.location())
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, str, d.location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no known layout matches %q", str)
+}
+
+// unmarshalTime parses the value of the selection into a time.Time. An empty
+// value leaves the time as its zero value.
+func (d *Decoder) unmarshalTime(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	if _, ok := tag.modifier("relativetime"); ok {
+		return d.unmarshalRelative(s, v, tag)
+	}
+	if fn, ok := tag.modifier("agg"); ok && (fn == "mindate" || fn == "maxdate") {
+		return d.unmarshalDateAggregate(s, v, tag, fn == "mindate")
+	}
+	if d.PreferVisible && !tag.readsAll() {
+		s = preferVisible(s)
+	}
+
+	str := tag.valFunc()(d, s)
+	if str == "" {
+		return nil
+	}
+
+	t, err := d.parseTime(str, tag)
+	if err != nil {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: typeConversionError,
+			Err:    err,
+			Val:    str,
+		}
+	}
+	v.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// unmarshalDateAggregate sets v to the earliest, or if min is false the
+// latest, of the times parsed from each element in the selection. Elements with
+// an empty value are skipped, and others that are not times are an error unless
+// the tag has the `skipinvalid` modifier. If no element has a time, v is left
+// as it is.
+func (d *Decoder) unmarshalDateAggregate(s *goquery.Selection, v reflect.Value, tag goqueryTag, min bool) error {
+	_, skip := tag.modifier("skipinvalid")
+	vf := tag.valFunc()
+
+	var res time.Time
+	found := false
+	for i := range s.Nodes {
+		str, err := d.transformValue(vf(d, s.Eq(i)), v, tag)
+		if err != nil {
+			return err
+		}
+		if str == "" {
+			continue
+		}
+		t, err := d.parseTime(str, tag)
+		if err != nil {
+			if skip {
+				continue
+			}
+			return &CannotUnmarshalError{
+				V:        v,
+				Reason:   typeConversionError,
+				Err:      err,
+				Val:      str,
+				FldOrIdx: i,
+			}
+		}
+		if !found || (min && t.Before(res)) || (!min && t.After(res)) {
+			res, found = t, true
+		}
+	}
+
+	if found {
+		v.Set(reflect.ValueOf(res))
+	}
+	return nil
+}
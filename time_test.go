@@ -0,0 +1,202 @@
+package goq
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const eventPage = `<html><body>
+<div class="event">
+	<time datetime="2024-03-10 18:30">Sunday evening</time>
+	<span class="zoned">2024-03-10T18:30:00+02:00</span>
+	<span class="custom">Mar 10, 2024 6:30PM</span>
+</div>
+</body></html>`
+
+func TestTime(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Naive  time.Time `goquery:"time,[datetime]"`
+		Zoned  time.Time `goquery:".zoned"`
+		Custom time.Time `goquery:".custom,layout:Jan 2, 2006 3:04PM"`
+		Empty  time.Time `goquery:".missing"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(eventPage), &a))
+	asrt.Equal(time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC), a.Naive)
+	asrt.Equal(time.Date(2024, 3, 10, 16, 30, 0, 0, time.UTC), a.Zoned.UTC())
+	asrt.Equal(time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC), a.Custom)
+	asrt.True(a.Empty.IsZero())
+}
+
+func TestTimeDefaultLocation(t *testing.T) {
+	asrt := assert.New(t)
+
+	loc := time.FixedZone("AEDT", 11*60*60)
+
+	var a struct {
+		Naive time.Time `goquery:"time,[datetime]"`
+		Zoned time.Time `goquery:".zoned"`
+	}
+
+	d := NewDecoder(strings.NewReader(eventPage))
+	d.DefaultLocation = loc
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal(time.Date(2024, 3, 10, 18, 30, 0, 0, loc), a.Naive)
+	asrt.Equal(loc, a.Naive.Location())
+	// An explicit zone in the document wins over the default location
+	asrt.Equal(time.Date(2024, 3, 10, 16, 30, 0, 0, time.UTC), a.Zoned.UTC())
+}
+
+func TestTimeError(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		When time.Time `goquery:"time"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(eventPage), &a)).unwind()
+	asrt.Equal("Sunday evening", e.val)
+	asrt.Equal(typeConversionError, e.last().Reason)
+}
+
+const feedPage = `<html><body>
+<ul>
+	<li><span class="ago">3 days ago</span></li>
+	<li><span class="ago">2h ago</span></li>
+	<li><span class="ago">an hour ago</span></li>
+	<li><span class="ago">in 2 weeks</span></li>
+	<li><span class="ago">just now</span></li>
+</ul>
+<span class="bad">a fortnight hence</span>
+<span class="bare">3 days</span>
+</body></html>`
+
+func TestRelativeTime(t *testing.T) {
+	asrt := assert.New(t)
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	var a struct {
+		Times []time.Time     `goquery:".ago,relativetime"`
+		Ages  []time.Duration `goquery:".ago,relativetime"`
+		Empty time.Time       `goquery:".missing,relativetime"`
+	}
+
+	d := NewDecoder(strings.NewReader(feedPage))
+	d.Now = func() time.Time { return now }
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal([]time.Time{
+		time.Date(2024, 3, 7, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 11, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 24, 12, 0, 0, 0, time.UTC),
+		now,
+	}, a.Times)
+	asrt.Equal([]time.Duration{72 * time.Hour, 2 * time.Hour, time.Hour, -14 * 24 * time.Hour, 0}, a.Ages)
+	asrt.True(a.Empty.IsZero())
+
+	var b struct {
+		Bad time.Time `goquery:".bad,relativetime"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(feedPage), &b))
+	asrt.Equal(typeConversionError, e.unwind().last().Reason)
+
+	var bare struct {
+		Bare time.Time `goquery:".bare,relativetime"`
+	}
+	e = checkErr(asrt, Unmarshal([]byte(feedPage), &bare))
+	asrt.Equal(typeConversionError, e.unwind().last().Reason)
+
+	var c struct {
+		Wrong int `goquery:".ago,relativetime"`
+	}
+	e = checkErr(asrt, Unmarshal([]byte(feedPage), &c))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}
+
+func TestReadingTime(t *testing.T) {
+	asrt := assert.New(t)
+
+	page := "<html><body><article>" + strings.Repeat("word ", 500) +
+		"</article><p class=\"short\">Just three words</p></body></html>"
+
+	type article struct {
+		Article time.Duration `goquery:"article,readingtime"`
+		Short   time.Duration `goquery:".short,readingtime"`
+		Missing time.Duration `goquery:".missing,readingtime"`
+	}
+
+	var a article
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Equal(150*time.Second, a.Article)
+	asrt.Equal(time.Second, a.Short)
+	asrt.Zero(a.Missing)
+
+	var b article
+	d := NewDecoder(strings.NewReader(page))
+	d.WordsPerMinute = 250
+	asrt.NoError(d.Decode(&b))
+	asrt.Equal(2*time.Minute, b.Article)
+
+	var c struct {
+		Minutes int `goquery:"article,readingtime"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(page), &c))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}
+
+const schedulePage = `<html><body>
+<ul class="schedule">
+	<li><time datetime="2024-05-03">Fri</time></li>
+	<li><time datetime="2024-04-28T09:00:00Z">Sun</time></li>
+	<li><time datetime="2024-06-15">Sat</time></li>
+	<li><time>TBA</time></li>
+</ul>
+<ul class="custom">
+	<li>3 May 2024</li>
+	<li>soon</li>
+	<li>1 May 2024</li>
+</ul>
+</body></html>`
+
+func TestDateAggregate(t *testing.T) {
+	asrt := assert.New(t)
+
+	type dateRange struct {
+		Start time.Time `goquery:"time,[datetime],agg:mindate"`
+		End   time.Time `goquery:"time,[datetime],agg:maxdate"`
+	}
+	var a struct {
+		Range   dateRange `goquery:".schedule"`
+		First   time.Time `goquery:".custom li,agg:mindate,skipinvalid,layout:2 January 2006"`
+		Missing time.Time `goquery:".missing,agg:mindate"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(schedulePage), &a))
+	asrt.Equal(time.Date(2024, 4, 28, 9, 0, 0, 0, time.UTC), a.Range.Start)
+	asrt.Equal(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), a.Range.End)
+	asrt.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), a.First)
+	asrt.True(a.Missing.IsZero())
+
+	var r struct {
+		Day time.Time `goquery:"time,[datetime],agg:mindate,regexp:^(\\d{4}-\\d{2}-\\d{2})"`
+	}
+	asrt.NoError(Unmarshal([]byte(schedulePage), &r))
+	asrt.Equal(time.Date(2024, 4, 28, 0, 0, 0, 0, time.UTC), r.Day)
+
+	var b struct {
+		First time.Time `goquery:".custom li,agg:mindate,layout:2 January 2006"`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(schedulePage), &b))
+	asrt.Equal(typeConversionError, e.unwind().last().Reason)
+
+	var c struct {
+		First int `goquery:".custom li,agg:mindate"`
+	}
+	e = checkErr(asrt, Unmarshal([]byte(schedulePage), &c))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}
@@ -16,6 +16,13 @@ const (
 	typeConversionError  = "a type conversion error occurred"
 	mapKeyUnmarshalError = "error unmarshaling a map key"
 	missingValueSelector = "at least one value selector must be passed to use as map index"
+	invalidModifier      = "invalid tag modifier"
+	invalidSelector      = "invalid element selector"
+	invalidTag           = "a nested type has an invalid tag"
+	duplicateSelector    = "another field has the same selector"
+	unknownField         = "no such field"
+	multipleRoots        = "root selector matched more than one element"
+	scriptStyleMatch     = "text was read from a script or style element"
 )
 
 // CannotUnmarshalError represents an error returned by the goquery Unmarshaler
@@ -2,10 +2,13 @@ package goq
 
 import (
 	"bytes"
+	"fmt"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 
@@ -25,7 +28,7 @@ func NodeSelector(nodes []*html.Node) *goquery.Selection {
 	return sel.AddNodes(nodes...)
 }
 
-type valFunc func(*goquery.Selection) string
+type valFunc func(*Decoder, *goquery.Selection) string
 
 type goqueryTag string
 
@@ -38,7 +41,7 @@ const (
 func (tag goqueryTag) preprocess(s *goquery.Selection) *goquery.Selection {
 	arr := strings.Split(string(tag), ",")
 	var offset int
-	for len(arr)-1 > offset && arr[offset][0] == prePfx {
+	for len(arr)-1 > offset && strings.HasPrefix(arr[offset], string(prePfx)) {
 		meth := arr[offset][1:]
 		v := reflect.ValueOf(s).MethodByName(meth)
 		if !v.IsValid() {
@@ -61,17 +64,61 @@ func (tag goqueryTag) selector(which int) string {
 		return ""
 	}
 	var offset int
-	for len(arr) > offset && arr[offset][0] == prePfx {
+	for len(arr) > offset && strings.HasPrefix(arr[offset], string(prePfx)) {
 		offset++
 	}
 	return arr[which+offset]
 }
 
+// greedyModifiers take the remainder of the tag as their argument, so that the
+// argument may itself contain commas. They must be the last modifier given.
+var greedyModifiers = map[string]bool{
+	"fallback": true,
+	"join":     true,
+	"layout":   true,
+	"regexp":   true,
+	"replace":  true,
+}
+
+// tokens returns the comma-separated parts of the tag that follow any
+// preprocessing methods and the element selector.
+func (tag goqueryTag) tokens() []string {
+	arr := strings.Split(string(tag), ",")
+	var offset int
+	for len(arr) > offset && strings.HasPrefix(arr[offset], string(prePfx)) {
+		offset++
+	}
+	if offset+1 >= len(arr) {
+		return nil
+	}
+	toks := arr[offset+1:]
+	for i, tok := range toks {
+		if greedyModifiers[strings.SplitN(tok, ":", 2)[0]] {
+			return append(toks[:i:i], strings.Join(toks[i:], ","))
+		}
+	}
+	return toks
+}
+
+// modifier looks for a `name` or `name:arg` token in the tag, returning the
+// argument and whether the modifier was present.
+func (tag goqueryTag) modifier(name string) (string, bool) {
+	for _, tok := range tag.tokens() {
+		if tok == name {
+			return "", true
+		}
+		if strings.HasPrefix(tok, name+":") {
+			return tok[len(name)+1:], true
+		}
+	}
+	return "", false
+}
+
 var (
-	textVal valFunc = func(s *goquery.Selection) string {
-		return strings.TrimSpace(s.Text())
+	textVal valFunc = func(d *Decoder, s *goquery.Selection) string {
+		return strings.TrimSpace(d.text(s))
 	}
-	htmlVal = func(s *goquery.Selection) string {
+	htmlVal = func(_ *Decoder, s *goquery.Selection) string {
 		str, _ := s.Html()
 		return strings.TrimSpace(str)
 	}
@@ -79,8 +126,50 @@ var (
 	vfCache sync.Map
 )
 
+// selectedVal returns the value of the selected option within a select
+// element, falling back to the first option as a browser would.
+func selectedVal(d *Decoder, s *goquery.Selection) string {
+	opts := s.Find("option")
+	opt := opts.FilterFunction(func(_ int, o *goquery.Selection) bool {
+		_, ok := o.Attr("selected")
+		return ok
+	}).First()
+	if opt.Length() == 0 {
+		opt = opts.First()
+	}
+	if val, ok := opt.Attr("value"); ok {
+		return val
+	}
+	return textVal(d, opt)
+}
+
+// fallbackText uses the text of the selection whenever f yields nothing.
+func fallbackText(f valFunc) valFunc {
+	return func(d *Decoder, s *goquery.Selection) string {
+		if str := strings.TrimSpace(f(d, s)); str != "" {
+			return str
+		}
+		return textVal(d, s)
+	}
+}
+
+// fallbackAttrs tries each of the attributes in turn whenever f yields nothing,
+// collapsing whitespace in every value tried.
+func fallbackAttrs(f valFunc, attrs []string) valFunc {
+	return func(d *Decoder, s *goquery.Selection) string {
+		str := collapseSpace(f(d, s))
+		for _, attr := range attrs {
+			if str != "" {
+				break
+			}
+			str = collapseSpace(s.AttrOr(strings.TrimSpace(attr), ""))
+		}
+		return str
+	}
+}
+
 func attrFunc(attr string) valFunc {
-	return func(s *goquery.Selection) string {
+	return func(_ *Decoder, s *goquery.Selection) string {
 		str, _ := s.Attr(attr)
 		return str
 	}
@@ -110,14 +199,160 @@ func (tag goqueryTag) valFunc() valFunc {
 		f = htmlVal
 	case src == "text":
 		f = textVal
+	case src == "contents":
+		f = contentsVal
+	case src == "preserve":
+		f = preserveVal
+	case strings.HasPrefix(src, "excludetext:"):
+		f = excludeTextVal(src[len("excludetext:"):])
+	case src == "id":
+		f = attrFunc("id")
+	case strings.HasPrefix(src, "attreq:"):
+		f = attrEqVal(src[len("attreq:"):])
+	case strings.HasPrefix(src, "flag:"):
+		f = flagVal(src[len("flag:"):])
+	case strings.HasPrefix(src, "selfattr:"):
+		f = attrFunc(src[len("selfattr:"):])
+	case src == "role":
+		f = roleVal
+	case src == "accdesc":
+		f = accDescVal
+	case src == "label":
+		f = labelVal
+	case src == "selected":
+		f = selectedVal
+	case src == "checkedvalues":
+		f = checkedVal
+	case src == "dir":
+		f = dirVal
+	case src == "isrtl":
+		f = isRTLVal
+	case src == "wordcount":
+		f = wordCountVal
+	case src == "charcount":
+		f = charCountVal
+	case src == "isunique":
+		f = isUniqueVal
+	case src == "iserrorpage":
+		f = isErrorPageVal
+	case src == "charset":
+		f = charsetVal
+	case strings.HasPrefix(src, "countattr:"):
+		_, invert := tag.modifier("invert")
+		f = countAttrVal(src[len("countattr:"):], invert)
+	case strings.HasPrefix(src, "countfind:"):
+		f = countFindVal(src[len("countfind:"):])
+	case strings.HasPrefix(src, "style:"):
+		f = styleVal(src[len("style:"):])
+	case strings.HasPrefix(src, "cssvar:"):
+		f = cssVarVal(src[len("cssvar:"):])
+	case strings.HasPrefix(src, "rdfa:"):
+		f = rdfaVal
+	case strings.HasPrefix(src, "dimension:"):
+		f = dimensionVal(src[len("dimension:"):])
+	case src == "ratio":
+		f = ratioVal
+	case src == "clamp":
+		f = clampVal
+	case src == "stars":
+		f = starsVal
+	case src == "positionfraction":
+		f = positionFractionVal
+	case src == "attrsjson":
+		f = attrsJSONVal
+	case src == "hasinlinehandler":
+		f = hasInlineHandlerVal
+	case src == "picturesrc":
+		f = pictureSrcVal
+	case src == "bgimage":
+		f = bgImageVal
 	default:
 		f = textVal
 	}
 
+	if _, ok := tag.modifier("fallbacktext"); ok {
+		f = fallbackText(f)
+	}
+	if attrs, ok := tag.modifier("fallback"); ok {
+		f = fallbackAttrs(f, strings.Split(attrs, ","))
+	}
+
 	vfCache.Store(tag, f)
 	return f
 }
 
+// readsText reports whether the value selector of the tag reads the text of
+// the matched elements rather than an attribute or a computed value. Tags that
+// parse a script body on purpose, with `jsonpath:` or `csv`, do not count.
+func (tag goqueryTag) readsText() bool {
+	for _, name := range []string{"jsonpath", "csv"} {
+		if _, ok := tag.modifier(name); ok {
+			return false
+		}
+	}
+	srcArr := strings.Split(string(tag), ",")
+	if len(srcArr) < 2 {
+		return true
+	}
+	src := srcArr[1]
+	if src == "" {
+		return true
+	}
+	for _, name := range textSources {
+		if src == name || strings.HasSuffix(name, ":") && strings.HasPrefix(src, name) {
+			return true
+		}
+	}
+	if src[0] == '[' {
+		return false
+	}
+	// Anything that is not a value selector, such as a modifier in its
+	// place, falls back to reading the text.
+	base := goqueryTag("," + src).valFunc()
+	return reflect.ValueOf(base).Pointer() == reflect.ValueOf(textVal).Pointer()
+}
+
+// textSources lists the value selectors that read the text of the matched
+// elements. Those ending in a colon take an argument.
+var textSources = []string{"text", "contents", "preserve", "excludetext:"}
+
+// readsAll reports whether the tag computes its value from every matched
+// element together, such as a count, so that PreferVisible leaves the
+// selection alone.
+func (tag goqueryTag) readsAll() bool {
+	for _, name := range []string{"join", "distinctcount"} {
+		if _, ok := tag.modifier(name); ok {
+			return true
+		}
+	}
+	srcArr := strings.Split(string(tag), ",")
+	if len(srcArr) < 2 {
+		return false
+	}
+	switch src := srcArr[1]; {
+	case src == "isunique" || src == "wordcount" || src == "charcount":
+		return true
+	case strings.HasPrefix(src, "countattr:") || strings.HasPrefix(src, "countfind:"):
+		return true
+	}
+	return false
+}
+
+// checkScriptStyle returns an error if the selection holds a script or style
+// element.
+func checkScriptStyle(s *goquery.Selection, v reflect.Value) error {
+	for _, n := range s.Nodes {
+		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
+			return &CannotUnmarshalError{
+				V:      v,
+				Reason: scriptStyleMatch,
+				Err:    fmt.Errorf("matched %s", nodePath(n)),
+			}
+		}
+	}
+	return nil
+}
+
 // popVal should allow us to handle arbitrarily nested maps as well as the
 // cleanly handling the possiblity of map[literal]literal by just delegating
 // back to `unmarshalByType`.
@@ -138,13 +373,33 @@ func (tag goqueryTag) popVal() goqueryTag {
 // CannotUnmarshalError, though an initial goquery error will pass through
 // directly.
 func Unmarshal(bs []byte, v interface{}) error {
-	d, err := goquery.NewDocumentFromReader(bytes.NewReader(bs))
+	return NewDecoder(bytes.NewReader(bs)).Decode(v)
+}
 
-	if err != nil {
-		return err
+// UnmarshalFields behaves like Unmarshal but only decodes the named top-level
+// fields of the struct v points to, leaving the others untouched. Naming a field
+// the struct does not have is an error.
+func UnmarshalFields(bs []byte, v interface{}, fields ...string) error {
+	d := NewDecoder(bytes.NewReader(bs))
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return d.Decode(v)
+	}
+
+	t := TypeDeref(rv.Type())
+	d.fields = map[string]bool{}
+
+	for _, name := range fields {
+		if t.Kind() != reflect.Struct {
+			return &CannotUnmarshalError{V: rv, Reason: unknownField, Val: name}
+		}
+		if _, ok := t.FieldByName(name); !ok {
+			return &CannotUnmarshalError{V: reflect.New(t).Elem(), Reason: unknownField, FldOrIdx: name}
+		}
+		d.fields[name] = true
 	}
 
-	return UnmarshalSelection(d.Selection, v)
+	return d.Decode(v)
 }
 
 func wrapUnmErr(err error, v reflect.Value) error {
@@ -162,6 +417,10 @@ func wrapUnmErr(err error, v reflect.Value) error {
 // UnmarshalSelection will unmarshal a goquery.goquery.Selection into an interface
 // appropriately annoated with goquery tags.
 func UnmarshalSelection(s *goquery.Selection, iface interface{}) error {
+	return (&Decoder{}).unmarshalSelection(s, iface)
+}
+
+func (d *Decoder) unmarshalSelection(s *goquery.Selection, iface interface{}) error {
 	v := reflect.ValueOf(iface)
 
 	// Must come before v.IsNil() else IsNil panics on NonPointer value
@@ -179,10 +438,16 @@ func UnmarshalSelection(s *goquery.Selection, iface interface{}) error {
 		return wrapUnmErr(u.UnmarshalHTML(s.Nodes), v)
 	}
 
-	return unmarshalByType(s, v, "")
+	return d.unmarshalByType(s, v, "")
 }
 
-func unmarshalByType(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+func (d *Decoder) unmarshalByType(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	// A tristate leaves its pointer nil, so it must be handled before indirect
+	// allocates one
+	if _, ok := tag.modifier("tristate"); ok && v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return unmarshalTristate(s, v)
+	}
+
 	u, v := indirect(v)
 
 	if u != nil {
@@ -195,33 +460,164 @@ func unmarshalByType(s *goquery.Selection, v reflect.Value, tag goqueryTag) erro
 		val = append(val, s.Nodes...)
 		v.Set(reflect.ValueOf(val))
 		return nil
+	case time.Time:
+		return d.unmarshalTime(s, v, tag)
+	case url.URL:
+		return d.unmarshalURL(s, v, tag)
 	}
 
 	t := v.Type()
 
 	switch t.Kind() {
 	case reflect.Struct:
-		return unmarshalStruct(s, v)
+		re, err := tag.regexp(v)
+		if err != nil {
+			return err
+		}
+		if re != nil {
+			return d.unmarshalRegexp(s, v, tag, re)
+		}
+		if prefix, ok := tag.modifier("datastruct"); ok {
+			return d.unmarshalDataStruct(s, v, prefix)
+		}
+		return d.unmarshalStruct(s, v)
 	case reflect.Slice:
-		return unmarshalSlice(s, v, tag)
+		return d.unmarshalSlice(s, v, tag)
 	case reflect.Array:
-		return unmarshalArray(s, v, tag)
+		return d.unmarshalArray(s, v, tag)
 	case reflect.Map:
-		return unmarshalMap(s, v, tag)
+		return d.unmarshalMap(s, v, tag)
 	default:
-		vf := tag.valFunc()
-		str := vf(s)
-		err := unmarshalLiteral(str, v)
+		return d.unmarshalScalar(s, v, tag)
+	}
+}
+
+// unmarshalScalar sets a literal value, either from the value selector of the
+// tag or from a modifier computing it over the whole selection.
+func (d *Decoder) unmarshalScalar(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	if fn, ok := tag.modifier("agg"); ok {
+		return d.unmarshalAggregate(s, v, tag, fn)
+	}
+	if _, ok := tag.modifier("relativetime"); ok {
+		return d.unmarshalRelative(s, v, tag)
+	}
+	for _, kind := range []string{"tel", "email"} {
+		if _, ok := tag.modifier(kind); ok {
+			return d.unmarshalContact(s, v, tag, kind)
+		}
+	}
+	if _, ok := tag.modifier("readingtime"); ok {
+		return d.unmarshalReadingTime(s, v, tag)
+	}
+	if _, ok := tag.modifier("distinctcount"); ok {
+		n, err := d.distinctCount(s, v, tag)
 		if err != nil {
-			return &CannotUnmarshalError{
+			return err
+		}
+		return setNumber(float64(n), v)
+	}
+	if name, ok := tag.modifier("tmpl"); ok {
+		return d.unmarshalTemplate(s, v, name)
+	}
+	if _, ok := tag.modifier("rowtotal"); ok {
+		sel, ok := tag.modifier("cells")
+		if !ok {
+			sel = "td"
+		}
+		return d.unmarshalRowTotal(s, v, sel)
+	}
+
+	if d.PreferVisible && !tag.readsAll() {
+		s = preferVisible(s)
+	}
+
+	if d.WarnOnScriptStyleMatch && tag.readsText() {
+		if err := checkScriptStyle(s, v); err != nil {
+			return err
+		}
+	}
+
+	vf := tag.valFunc()
+	str := vf(d, s)
+
+	if arg, ok := tag.modifier("join"); ok {
+		str = d.joinValues(s, vf, tag, arg)
+	}
+
+	str, err := d.transformValue(str, v, tag)
+	if err != nil {
+		return err
+	}
+
+	err = unmarshalLiteral(str, v)
+	if err != nil {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: typeConversionError,
+			Err:    err,
+			Val:    str,
+		}
+	}
+	return nil
+}
+
+// transformValue applies the modifiers of the tag that rewrite a value read
+// from the document, in order: jsonpath, replace, regexp, slug and truncate.
+func (d *Decoder) transformValue(str string, v reflect.Value, tag goqueryTag) (string, error) {
+	if path, ok := tag.modifier("jsonpath"); ok {
+		val, err := jsonPathVal(str, path)
+		if err != nil {
+			return "", &CannotUnmarshalError{
 				V:      v,
 				Reason: typeConversionError,
 				Err:    err,
 				Val:    str,
 			}
 		}
-		return nil
+		str = val
+	}
+
+	if arg, ok := tag.modifier("replace"); ok {
+		pairs, err := parseReplacements(arg)
+		if err != nil {
+			return "", &CannotUnmarshalError{
+				V:      v,
+				Reason: invalidModifier,
+				Err:    err,
+				Val:    arg,
+			}
+		}
+		for _, p := range pairs {
+			str = strings.ReplaceAll(str, p[0], p[1])
+		}
+		str = strings.TrimSpace(str)
+	}
+
+	re, err := tag.regexp(v)
+	if err != nil {
+		return "", err
+	}
+	if re != nil {
+		str = regexpMatch(re, str)
+	}
+
+	if _, ok := tag.modifier("slug"); ok {
+		str = slugify(str)
+	}
+
+	if arg, ok := tag.modifier("truncate"); ok {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			return "", &CannotUnmarshalError{
+				V:      v,
+				Reason: invalidModifier,
+				Err:    fmt.Errorf("truncate needs a number of characters, not %q", arg),
+				Val:    arg,
+			}
+		}
+		str = truncate(str, n)
 	}
+	return str, nil
 }
 
 func unmarshalLiteral(s string, v reflect.Value) error {
@@ -265,16 +661,66 @@ func unmarshalLiteral(s string, v reflect.Value) error {
 	return nil
 }
 
-func unmarshalStruct(s *goquery.Selection, v reflect.Value) error {
+func (d *Decoder) unmarshalStruct(s *goquery.Selection, v reflect.Value) error {
+	// Only the outermost struct is limited to the requested fields
+	only := d.fields
+	d.fields = nil
+
+	return d.unmarshalFields(s, v, only, nil)
+}
+
+// unmarshalFields unmarshals the fields of the struct v, skipping any named in
+// shadowed. The fields of untagged embedded structs are unmarshaled from the
+// same selection as if they belonged to v, unless a field of the same name is
+// closer to the surface, following Go's rules for promoted fields.
+func (d *Decoder) unmarshalFields(s *goquery.Selection, v reflect.Value, only, shadowed map[string]bool) error {
 	t := v.Type()
 
+	inner := map[string]bool{}
+	for name := range shadowed {
+		inner[name] = true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		inner[t.Field(i).Name] = true
+	}
+
 	for i := 0; i < t.NumField(); i++ {
-		tag := goqueryTag(t.Field(i).Tag.Get(tagName))
+		field := t.Field(i)
+		if shadowed[field.Name] {
+			continue
+		}
+
+		tag := goqueryTag(field.Tag.Get(tagName))
 
 		if tag == ignoreTag {
 			continue
 		}
 
+		if tag == "" && field.Anonymous {
+			if fv, ok := embeddedStruct(v.Field(i)); ok {
+				fieldsOnly := only
+				if only[field.Name] {
+					fieldsOnly = nil
+				}
+				if err := d.unmarshalFields(s, fv, fieldsOnly, inner); err != nil {
+					return &CannotUnmarshalError{
+						Reason:   typeConversionError,
+						Err:      err,
+						V:        v,
+						FldOrIdx: field.Name,
+					}
+				}
+				continue
+			}
+			if !v.Field(i).CanSet() {
+				continue
+			}
+		}
+
+		if only != nil && !only[field.Name] {
+			continue
+		}
+
 		// If tag is empty and the object doesn't implement Unmarshaler, skip
 		if tag == "" {
 			if u, _ := indirect(v.Field(i)); u == nil {
@@ -282,26 +728,54 @@ func unmarshalStruct(s *goquery.Selection, v reflect.Value) error {
 			}
 		}
 
+		// An empty element selector refers to the current element itself
 		sel := tag.preprocess(s)
-		if tag != "" {
-			selStr := tag.selector(0)
+		if selStr := tag.selector(0); selStr != "" {
 			sel = sel.Find(selStr)
 		}
 
-		err := unmarshalByType(sel, v.Field(i), tag)
+		sel, err := d.scopeSelection(sel, v.Field(i), tag)
+		if err == nil {
+			err = d.unmarshalAt(field.Name, sel, v.Field(i), tag)
+		}
 		if err != nil {
 			return &CannotUnmarshalError{
 				Reason:   typeConversionError,
 				Err:      err,
 				V:        v,
-				FldOrIdx: t.Field(i).Name,
+				FldOrIdx: field.Name,
 			}
 		}
 	}
 	return nil
 }
 
-func unmarshalArray(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+// embeddedStruct returns the struct held by an embedded field, allocating it
+// if it is a nil pointer. It reports false for fields that are not structs,
+// that implement Unmarshaler, or that are unexported nil pointers.
+func embeddedStruct(fv reflect.Value) (reflect.Value, bool) {
+	if fv.Kind() == reflect.Ptr {
+		if fv.Type().Elem().Kind() != reflect.Struct {
+			return fv, false
+		}
+		if fv.IsNil() {
+			if !fv.CanSet() {
+				return fv, false
+			}
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+	if fv.Kind() != reflect.Struct || fv.Type() == reflect.TypeOf(time.Time{}) {
+		return fv, false
+	}
+	if reflect.PtrTo(fv.Type()).Implements(reflect.TypeOf((*Unmarshaler)(nil)).Elem()) {
+		return fv, false
+	}
+	return fv, true
+}
+
+func (d *Decoder) unmarshalArray(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
 	if v.Type().Len() != len(s.Nodes) {
 		return &CannotUnmarshalError{
 			Reason: arrayLengthMismatch,
@@ -310,7 +784,7 @@ func unmarshalArray(s *goquery.Selection, v reflect.Value, tag goqueryTag) error
 	}
 
 	for i := 0; i < v.Type().Len(); i++ {
-		err := unmarshalByType(s.Eq(i), v.Index(i), tag)
+		err := d.unmarshalAtIndex(i, s.Eq(i), v.Index(i), tag)
 		if err != nil {
 			return &CannotUnmarshalError{
 				Reason:   typeConversionError,
@@ -324,14 +798,48 @@ func unmarshalArray(s *goquery.Selection, v reflect.Value, tag goqueryTag) error
 	return nil
 }
 
-func unmarshalSlice(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+func (d *Decoder) unmarshalSlice(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
 	slice := v
 	eleT := v.Type().Elem()
+	_, nilEmpty := tag.modifier("nilempty")
+	_, tristate := tag.modifier("tristate")
+
+	if sep, ok := tag.modifier("groupby"); ok && TypeDeref(eleT).Kind() == reflect.Slice {
+		return d.unmarshalGroups(s, v, tag, sep)
+	}
+	if prefix, ok := tag.modifier("classes"); ok {
+		return unmarshalStrings(classTokens(s, prefix), v)
+	}
+	if _, ok := tag.modifier("links"); ok {
+		return d.unmarshalLinks(s, v, tag)
+	}
+	if _, ok := tag.modifier("breadcrumbs"); ok {
+		return d.unmarshalBreadcrumbs(s, v)
+	}
+	if _, ok := tag.modifier("csv"); ok {
+		return d.unmarshalCSV(s, v, tag)
+	}
+	if _, ok := tag.modifier("headers"); ok && TypeDeref(eleT).Kind() == reflect.Map {
+		return d.unmarshalHeaders(s, v, tag)
+	}
+	if _, ok := tag.modifier("table"); ok && TypeDeref(eleT).Kind() == reflect.Slice {
+		return d.unmarshalTable(s, v)
+	}
 
 	for i := 0; i < s.Length(); i++ {
+		// Keep empty elements distinct from zero values when asked to
+		if nilEmpty && eleT.Kind() == reflect.Ptr && strings.TrimSpace(tag.valFunc()(d, s.Eq(i))) == "" {
+			v = reflect.Append(v, reflect.Zero(eleT))
+			continue
+		}
+
 		newV := reflect.New(TypeDeref(eleT))
+		if tristate {
+			// Unmarshal into the pointer itself so that it may be left nil
+			newV = reflect.New(eleT).Elem()
+		}
 
-		err := unmarshalByType(s.Eq(i), newV, tag)
+		err := d.unmarshalAtIndex(i, s.Eq(i), newV, tag)
 
 		if err != nil {
 			return &CannotUnmarshalError{
@@ -365,12 +873,35 @@ func childrenUntilMatch(s *goquery.Selection, sel string) *goquery.Selection {
 	return s.Filter(sel)
 }
 
-func unmarshalMap(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+func (d *Decoder) unmarshalMap(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
 	// Make new map here because indirect for some Reason doesn't help us out
 	if v.IsNil() {
 		v.Set(reflect.MakeMap(v.Type()))
 	}
 
+	if _, ok := tag.modifier("kv"); ok {
+		return d.unmarshalKV(s, v, tag)
+	}
+	if _, ok := tag.modifier("dl"); ok {
+		return d.unmarshalDL(s, v)
+	}
+	if _, ok := tag.modifier("grid"); ok {
+		return d.unmarshalGrid(s, v)
+	}
+	if _, ok := tag.modifier("thtd"); ok {
+		return d.unmarshalTHTD(s, v)
+	}
+	if _, ok := tag.modifier("indexmap"); ok {
+		return d.unmarshalIndexMap(s, v)
+	}
+	if keyBy, ok := tag.modifier("checkstate"); ok {
+		return d.unmarshalCheckState(s, v, keyBy)
+	}
+	if src, ok := tag.modifier("groupcount"); ok {
+		emptyKey, _ := tag.modifier("emptykey")
+		return d.unmarshalGroupCount(s, v, src, emptyKey)
+	}
+
 	keyT, eleT := v.Type().Key(), v.Type().Elem()
 
 	if tag.selector(1) == "" {
@@ -392,19 +923,19 @@ func unmarshalMap(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
 	s.EachWithBreak(func(_ int, subS *goquery.Selection) bool {
 		newK, newV := reflect.New(TypeDeref(keyT)), reflect.New(TypeDeref(eleT))
 
-		err = unmarshalByType(subS, newK, tag)
+		err = d.unmarshalByType(subS, newK, tag)
 		if err != nil {
 			err = &CannotUnmarshalError{
 				Reason:   mapKeyUnmarshalError,
 				V:        v,
 				Err:      err,
 				FldOrIdx: newK.Interface(),
-				Val:      valTag.valFunc()(subS),
+				Val:      valTag.valFunc()(d, subS),
 			}
 			return false
 		}
 
-		err = unmarshalByType(subS, newV, valTag)
+		err = d.unmarshalAtIndex(newK.Elem().Interface(), subS, newV, valTag)
 		if err != nil {
 			return false
 		}
@@ -660,3 +660,376 @@ func TestHNPage(t *testing.T) {
 	asrt.Equal("stackexchange.com", i2.Site)
 	asrt.Equal(7, int(i2.Points))
 }
+
+const formPage = `<html><body>
+<form>
+	<select name="country">
+		<option value="au">Australia</option>
+		<option value="nz" selected>New Zealand</option>
+	</select>
+	<select name="size">
+		<option>Small</option>
+		<option value="l">Large</option>
+	</select>
+	<input type="checkbox" name="topics" value="go" checked>
+	<input type="checkbox" name="topics" value="rust">
+	<input type="checkbox" name="topics" value="html" checked="checked">
+	<input type="checkbox" name="topics" checked>
+	<input type="radio" name="plan" value="free">
+	<input type="radio" name="plan" value="pro">
+</form>
+</body></html>`
+
+func TestSelectedOption(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Country string `goquery:"select[name=country],selected"`
+		Size    string `goquery:"select[name=size],selected"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(formPage), &a))
+	asrt.Equal("nz", a.Country)
+	// With nothing selected the first option's text is used as its value
+	asrt.Equal("Small", a.Size)
+}
+
+const catalogPage = `<html><body>
+<div class="category">
+	<h2>Fruit</h2>
+	<div class="product">
+		<span class="name">Apple</span>
+		<p class="review">Crisp</p>
+		<p class="review">Sweet</p>
+	</div>
+	<div class="product">
+		<span class="name">Pear</span>
+	</div>
+</div>
+<div class="category">
+	<h2>Veg</h2>
+	<div class="product">
+		<span class="name">Leek</span>
+		<p class="review">Mild</p>
+		<p class="review">Long</p>
+		<p class="review">Green</p>
+	</div>
+</div>
+<p class="review">Stray</p>
+</body></html>`
+
+type category struct {
+	Name     string    `goquery:"h2"`
+	Products []product `goquery:".product"`
+}
+
+type product struct {
+	Name    string   `goquery:".name"`
+	Reviews []review `goquery:".review"`
+}
+
+type review struct {
+	Text string `goquery:",text"`
+}
+
+func TestNestedSliceScoping(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Categories []category `goquery:".category"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(catalogPage), &a))
+	asrt.Len(a.Categories, 2)
+
+	fruit, veg := a.Categories[0], a.Categories[1]
+	asrt.Equal("Fruit", fruit.Name)
+	asrt.Len(fruit.Products, 2)
+	asrt.Equal("Apple", fruit.Products[0].Name)
+	asrt.Len(fruit.Products[0].Reviews, 2)
+	asrt.Len(fruit.Products[1].Reviews, 0)
+
+	asrt.Equal("Veg", veg.Name)
+	asrt.Len(veg.Products, 1)
+	asrt.Len(veg.Products[0].Reviews, 3)
+	asrt.Equal("Green", veg.Products[0].Reviews[2].Text)
+}
+
+const a11yPage = `<html><body>
+<img class="logo" src="logo.png" alt="Company logo">
+<p>Uses <abbr title="HyperText Markup Language">HTML</abbr> and <abbr>CSS</abbr></p>
+</body></html>`
+
+func TestFallbackText(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Alt    string   `goquery:"img.logo,[alt],fallbacktext"`
+		Titles []string `goquery:"abbr,[title],fallbacktext"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(a11yPage), &a))
+	asrt.Equal("Company logo", a.Alt)
+	asrt.Equal([]string{"HyperText Markup Language", "CSS"}, a.Titles)
+}
+
+const fallbackPage = `<html><body>
+<button id="close" title="  " aria-label=" Close   dialog "> </button>
+<button id="save" title="Save changes"></button>
+<button id="send" title="Send">Send  now</button>
+</body></html>`
+
+func TestFallbackAttrs(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Close string   `goquery:"#close,fallback:title,aria-label"`
+		Save  string   `goquery:"#save,fallback:title,aria-label"`
+		Send  string   `goquery:"#send,fallback:title,aria-label"`
+		Alts  []string `goquery:"button,[data-name],fallback:aria-label, title"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(fallbackPage), &a))
+	asrt.Equal("Close dialog", a.Close)
+	asrt.Equal("Save changes", a.Save)
+	asrt.Equal("Send now", a.Send)
+	asrt.Equal([]string{"Close dialog", "Save changes", "Send"}, a.Alts)
+}
+
+const sparsePage = `<html><body>
+<table><tr>
+	<td class="cell"><span class="amount">3</span></td>
+	<td class="cell"> </td>
+	<td class="cell"><span class="amount">0</span></td>
+	<td class="cell"></td>
+</tr></table>
+</body></html>`
+
+type price struct {
+	Amount int `goquery:".amount"`
+}
+
+func TestNilEmptySlice(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Prices []*price `goquery:".cell,nilempty"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(sparsePage), &a))
+	asrt.Len(a.Prices, 4)
+	asrt.Equal(3, a.Prices[0].Amount)
+	asrt.Nil(a.Prices[1])
+	asrt.NotNil(a.Prices[2])
+	asrt.Equal(0, a.Prices[2].Amount)
+	asrt.Nil(a.Prices[3])
+
+	// Without the modifier empty cells are decoded and fail conversion
+	var b struct {
+		Prices []*price `goquery:".cell"`
+	}
+	checkErr(asrt, Unmarshal([]byte(sparsePage), &b))
+}
+
+func TestNilEmptyAttr(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body><table><tr>
+	<td class="c" data-p="3"></td>
+	<td class="c" data-p="">7</td>
+	<td class="c" data-p="5">x</td>
+	<td class="c">9</td>
+</tr></table></body></html>`
+
+	var a struct {
+		Prices []*int `goquery:".c,[data-p],nilempty"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	if asrt.Len(a.Prices, 4) {
+		asrt.Equal(3, *a.Prices[0])
+		asrt.Nil(a.Prices[1])
+		asrt.Equal(5, *a.Prices[2])
+		asrt.Nil(a.Prices[3])
+	}
+}
+
+func TestCheckedValues(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Topics []string `goquery:"input[name=topics],checkedvalues"`
+		Plan   []string `goquery:"input[name=plan],checkedvalues"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(formPage), &a))
+	asrt.Equal([]string{"go", "html", "on"}, a.Topics)
+	asrt.Empty(a.Plan)
+}
+
+func TestIDSelector(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Header    string `goquery:"h2,id"`
+		HeaderAtt string `goquery:"h2,[id]"`
+		NoID      string `goquery:"#resources .resource,id"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(testPage), &a))
+	asrt.Equal("anchor-header", a.Header)
+	asrt.Equal("anchor-header", a.HeaderAtt)
+	asrt.Equal("", a.NoID)
+}
+
+type point struct {
+	X int `goquery:",selfattr:data-x"`
+	Y int `goquery:",selfattr:data-y"`
+}
+
+func TestSelfAttr(t *testing.T) {
+	asrt := assert.New(t)
+
+	const page = `<html><body><div class="gallery">
+<img class="tile" data-x="0" data-y="10" src="a.jpg">
+<img class="tile" data-x="120" data-y="-4" src="b.jpg">
+</div></body></html>`
+
+	var a struct {
+		Tiles []struct {
+			point
+			Src string `goquery:",selfattr:src"`
+		} `goquery:".tile"`
+		First point `goquery:".tile"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(page), &a))
+	asrt.Len(a.Tiles, 2)
+	asrt.Equal(point{X: 0, Y: 10}, a.Tiles[0].point)
+	asrt.Equal(point{X: 120, Y: -4}, a.Tiles[1].point)
+	asrt.Equal("b.jpg", a.Tiles[1].Src)
+	asrt.Equal(point{X: 0, Y: 10}, a.First)
+}
+
+func TestUnmarshalFields(t *testing.T) {
+	asrt := assert.New(t)
+
+	var p Page
+	asrt.NoError(UnmarshalFields([]byte(testPage), &p, "Resources"))
+	asrt.Len(p.Resources, 5)
+	asrt.False(p.FooBar.unmarshalWasCalled)
+
+	var m MapTest
+	asrt.NoError(UnmarshalFields([]byte(testPage), &m, "Names", "Nested"))
+	asrt.Len(m.Names, 3)
+	asrt.Len(m.Nested["first"], 3)
+	asrt.Nil(m.Resources)
+
+	e := checkErr(asrt, UnmarshalFields([]byte(testPage), &p, "Resources", "Missing"))
+	asrt.Equal(unknownField, e.Reason)
+	asrt.Contains(e.Error(), "goq.Page.Missing")
+
+	e = checkErr(asrt, UnmarshalFields([]byte(testPage), nil, "Resources"))
+	asrt.Equal(nonPointer, e.Reason)
+}
+
+type basePage struct {
+	Title   string `goquery:"title"`
+	Heading string `goquery:"h1"`
+	Links   int    `goquery:"body,countfind:a"`
+}
+
+// ProductPage is exported so that it can be embedded by pointer.
+type ProductPage struct {
+	basePage
+	Heading string `goquery:"h1.product-name"`
+}
+
+type detailPage struct {
+	*ProductPage
+	Price string `goquery:".price"`
+}
+
+const derivedPage = `<html><head><title>Shop</title></head><body>
+<h1 class="site">ACME</h1>
+<h1 class="product-name">Anvil</h1>
+<span class="price">$10</span>
+<a href="/">Home</a><a href="/cart">Cart</a>
+</body></html>`
+
+func TestEmbeddedStructs(t *testing.T) {
+	asrt := assert.New(t)
+
+	var p ProductPage
+	asrt.NoError(Unmarshal([]byte(derivedPage), &p))
+	asrt.Equal("Shop", p.Title)
+	asrt.Equal("Anvil", p.Heading)
+	asrt.Equal("", p.basePage.Heading)
+	asrt.Equal(2, p.Links)
+
+	var d detailPage
+	asrt.NoError(Unmarshal([]byte(derivedPage), &d))
+	asrt.Equal("Shop", d.Title)
+	asrt.Equal("Anvil", d.Heading)
+	asrt.Equal("$10", d.Price)
+
+	var f ProductPage
+	asrt.NoError(UnmarshalFields([]byte(derivedPage), &f, "Title"))
+	asrt.Equal("Shop", f.Title)
+	asrt.Equal("", f.Heading)
+}
+
+func TestUnexportedEmbeddedPointer(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		*basePage
+		Price string `goquery:".price"`
+	}
+	asrt.NoError(Unmarshal([]byte(derivedPage), &a))
+	asrt.Nil(a.basePage)
+	asrt.Equal("$10", a.Price)
+}
+
+const frameworkPage = `<html><body>
+<div id="app" :class="{ active: open }" x-on:click="open = !open" @keyup.enter="submit">
+	<a v-bind:href="/vue" hx-get="/items?page=2" hx-target="#list">Vue</a>
+	<a v-bind:href="/other">Other</a>
+	<button hx-post="/save" hx-swap="outerHTML">Save</button>
+</div>
+</body></html>`
+
+func TestFrameworkAttributes(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Class  string   `goquery:"#app,[:class]"`
+		Click  string   `goquery:"#app,[x-on:click]"`
+		KeyUp  string   `goquery:"#app,[@keyup.enter]"`
+		Binds  []string `goquery:"a,[v-bind:href]"`
+		Bound  int      `goquery:"#app,countfind:[v-bind\\:href]"`
+		Get    string   `goquery:"[hx-get],[hx-get]"`
+		Target string   `goquery:"[hx-target],[hx-target]"`
+		Swap   string   `goquery:"[hx-swap='outerHTML'],[hx-post]"`
+		Page   int      `goquery:"[hx-get],[hx-get],regexp:page=(\\d+)"`
+		Parent string   `goquery:"[hx-post],[x-on:click],parent"`
+		Vue    struct {
+			Href string `goquery:",[v-bind:href]"`
+		} `goquery:"[v-bind\\:href]"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(frameworkPage), &a))
+	asrt.Equal("{ active: open }", a.Class)
+	asrt.Equal("open = !open", a.Click)
+	asrt.Equal("submit", a.KeyUp)
+	asrt.Equal([]string{"/vue", "/other"}, a.Binds)
+	asrt.Equal(2, a.Bound)
+	asrt.Equal("/items?page=2", a.Get)
+	asrt.Equal("#list", a.Target)
+	asrt.Equal("/save", a.Swap)
+	asrt.Equal(2, a.Page)
+	asrt.Equal("open = !open", a.Parent)
+	asrt.Equal("/vue", a.Vue.Href)
+
+	asrt.NoError(ValidateTags(&a, ValidateOptions{}))
+}
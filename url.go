@@ -0,0 +1,163 @@
+package goq
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// resolveURL resolves ref against the decoder's BaseURL, returning ref as is
+// if there is no BaseURL or ref cannot be parsed.
+func (d *Decoder) resolveURL(ref string) string {
+	if d.BaseURL == nil || ref == "" {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return d.BaseURL.ResolveReference(u).String()
+}
+
+// pictureSrcVal returns the URL of the image shown by the first matched
+// `<picture>` element, resolved against the decoder's BaseURL. The src of its
+// fallback `<img>` is preferred, and otherwise the first candidate in the
+// srcset of its first `<source>` that has one is used.
+func pictureSrcVal(d *Decoder, s *goquery.Selection) string {
+	pic := s.First()
+	img := pic.Find("img")
+	if goquery.NodeName(pic) == "img" {
+		img = pic
+	}
+	if src := strings.TrimSpace(img.AttrOr("src", "")); src != "" {
+		return d.resolveURL(src)
+	}
+
+	srcset := pic.Find("source[srcset]").AttrOr("srcset", "")
+	first, _, _ := strings.Cut(srcset, ",")
+	if fields := strings.Fields(first); len(fields) > 0 {
+		return d.resolveURL(fields[0])
+	}
+	return ""
+}
+
+// unmarshalURL parses the value of the selection into a url.URL, resolved
+// against the decoder's BaseURL. An empty value leaves the URL as its zero
+// value.
+func (d *Decoder) unmarshalURL(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	if d.PreferVisible && !tag.readsAll() {
+		s = preferVisible(s)
+	}
+	str := tag.valFunc()(d, s)
+	if str == "" {
+		return nil
+	}
+
+	u, err := url.Parse(str)
+	if err != nil {
+		return &CannotUnmarshalError{
+			V:      v,
+			Reason: typeConversionError,
+			Err:    err,
+			Val:    str,
+		}
+	}
+	if d.BaseURL != nil {
+		u = d.BaseURL.ResolveReference(u)
+	}
+	if arg, ok := tag.modifier("stripquery"); ok {
+		if err := stripQuery(u, strings.Split(arg, ";")); err != nil {
+			return &CannotUnmarshalError{
+				V:      v,
+				Reason: invalidModifier,
+				Err:    err,
+				Val:    arg,
+			}
+		}
+	}
+	v.Set(reflect.ValueOf(*u))
+	return nil
+}
+
+// stripQuery removes the query parameters of u whose names match any of the
+// glob patterns, keeping the order and encoding of the others.
+func stripQuery(u *url.URL, patterns []string) error {
+	for _, pat := range patterns {
+		if _, err := path.Match(pat, ""); err != nil {
+			return fmt.Errorf("bad pattern %q: %w", pat, err)
+		}
+	}
+
+	var kept []string
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil {
+			key = name
+		}
+		strip := false
+		for _, pat := range patterns {
+			if ok, _ := path.Match(pat, key); ok {
+				strip = true
+				break
+			}
+		}
+		if param != "" && !strip {
+			kept = append(kept, param)
+		}
+	}
+	u.RawQuery = strings.Join(kept, "&")
+	u.ForceQuery = false
+	return nil
+}
+
+// unmarshalLinks fills the slice v with the hrefs of the links within the
+// selection, resolved against the decoder's BaseURL. Elements may be url.URL
+// values or strings. With the `unique` modifier repeated links are skipped.
+func (d *Decoder) unmarshalLinks(s *goquery.Selection, v reflect.Value, tag goqueryTag) error {
+	_, unique := tag.modifier("unique")
+	seen := map[string]bool{}
+
+	slice := v
+	eleT := v.Type().Elem()
+
+	links := s.Find("a[href]")
+	for i := range links.Nodes {
+		href := d.resolveURL(strings.TrimSpace(links.Eq(i).AttrOr("href", "")))
+		if href == "" || (unique && seen[href]) {
+			continue
+		}
+		seen[href] = true
+
+		newV := reflect.New(TypeDeref(eleT))
+		var err error
+		if u, ok := newV.Interface().(*url.URL); ok {
+			var parsed *url.URL
+			if parsed, err = url.Parse(href); err == nil {
+				*u = *parsed
+			}
+		} else {
+			err = unmarshalLiteral(href, newV.Elem())
+		}
+		if err != nil {
+			return &CannotUnmarshalError{
+				Reason:   typeConversionError,
+				Err:      err,
+				V:        v,
+				Val:      href,
+				FldOrIdx: i,
+			}
+		}
+
+		if eleT.Kind() != reflect.Ptr {
+			newV = newV.Elem()
+		}
+		v = reflect.Append(v, newV)
+	}
+
+	slice.Set(v)
+	return nil
+}
@@ -0,0 +1,169 @@
+package goq
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const bgPage = `<html><body>
+<div class="hero" style="background-image: url('/img/single.jpg')"></div>
+<div class="hero" style="color: red; background-image:url(&quot;img/double.jpg&quot;)"></div>
+<div class="hero" style="background: #fff url( https://cdn.example.com/bare.png ) no-repeat"></div>
+<div class="hero" style="color: blue"></div>
+<a class="link" href="../about?x=1">About</a>
+</body></html>`
+
+func TestURL(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Link    url.URL  `goquery:".link,[href]"`
+		LinkPtr *url.URL `goquery:".link,[href]"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(bgPage), &a))
+	asrt.Equal("../about?x=1", a.Link.String())
+
+	base, _ := url.Parse("https://example.com/blog/post/")
+	d := NewDecoder(strings.NewReader(bgPage))
+	d.BaseURL = base
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal("https://example.com/blog/about?x=1", a.Link.String())
+	asrt.Equal("https://example.com/blog/about?x=1", a.LinkPtr.String())
+	asrt.Equal("1", a.LinkPtr.Query().Get("x"))
+}
+
+const trackingPage = `<html><body>
+<a class="product" href="/shoes?id=7&utm_source=mail&size=10&utm_medium=email&fbclid=abc#reviews">Shoes</a>
+<a class="product" href="/hats?utm_campaign=spring">Hats</a>
+<a class="product" href="/bags?id=3">Bags</a>
+</body></html>`
+
+func TestStripQuery(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		First    *url.URL  `goquery:"a.product,[href],stripquery:utm_*"`
+		Products []url.URL `goquery:"a.product,[href],stripquery:utm_*;fbclid"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(trackingPage), &a))
+	asrt.Equal("/shoes?id=7&size=10&fbclid=abc#reviews", a.First.String())
+	if asrt.Len(a.Products, 3) {
+		asrt.Equal("/shoes?id=7&size=10#reviews", a.Products[0].String())
+		asrt.Equal("/hats", a.Products[1].String())
+		asrt.Equal("/bags?id=3", a.Products[2].String())
+	}
+
+	var b struct {
+		Link url.URL `goquery:"a.product,[href],stripquery:utm_["`
+	}
+	e := checkErr(asrt, Unmarshal([]byte(trackingPage), &b))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}
+
+const picturePage = `<html><body>
+<picture class="hero">
+	<source media="(min-width: 800px)" srcset="/img/hero-wide.webp 1x, /img/hero-wide@2x.webp 2x">
+	<source srcset="/img/hero.webp">
+	<img src="img/hero.jpg" alt="Hero">
+</picture>
+<picture class="nofallback">
+	<source type="image/avif">
+	<source srcset=" /img/thumb-480.avif 480w, /img/thumb-960.avif 960w">
+	<img alt="Thumb">
+</picture>
+<picture class="empty"></picture>
+</body></html>`
+
+func TestPictureSrc(t *testing.T) {
+	asrt := assert.New(t)
+
+	type pictures struct {
+		Hero       string   `goquery:".hero,picturesrc"`
+		NoFallback *url.URL `goquery:".nofallback,picturesrc"`
+		Empty      string   `goquery:".empty,picturesrc"`
+	}
+
+	var a pictures
+	asrt.NoError(Unmarshal([]byte(picturePage), &a))
+	asrt.Equal("img/hero.jpg", a.Hero)
+	asrt.Equal("/img/thumb-480.avif", a.NoFallback.String())
+	asrt.Equal("", a.Empty)
+
+	var b pictures
+	base, _ := url.Parse("https://example.com/news/")
+	d := NewDecoder(strings.NewReader(picturePage))
+	d.BaseURL = base
+	asrt.NoError(d.Decode(&b))
+	asrt.Equal("https://example.com/news/img/hero.jpg", b.Hero)
+	asrt.Equal("https://example.com/img/thumb-480.avif", b.NoFallback.String())
+}
+
+func TestBackgroundImage(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Images []string   `goquery:".hero,bgimage"`
+		URLs   []*url.URL `goquery:".hero,bgimage"`
+	}
+
+	asrt.NoError(Unmarshal([]byte(bgPage), &a))
+	asrt.Equal([]string{"/img/single.jpg", "img/double.jpg", "https://cdn.example.com/bare.png", ""}, a.Images)
+
+	base, _ := url.Parse("https://example.com/gallery/")
+	d := NewDecoder(strings.NewReader(bgPage))
+	d.BaseURL = base
+	a.Images, a.URLs = nil, nil
+	asrt.NoError(d.Decode(&a))
+	asrt.Equal([]string{
+		"https://example.com/img/single.jpg",
+		"https://example.com/gallery/img/double.jpg",
+		"https://cdn.example.com/bare.png",
+		"",
+	}, a.Images)
+	asrt.Len(a.URLs, 4)
+	asrt.Equal("https://example.com/gallery/img/double.jpg", a.URLs[1].String())
+	asrt.Equal(url.URL{}, *a.URLs[3])
+}
+
+const linksPage = `<html><body>
+<nav>
+	<a href="/">Home</a>
+	<a href="/docs/">Docs</a>
+	<a href="https://example.org/ext">External</a>
+	<a href="/">Home again</a>
+	<a>No href</a>
+	<a href="">Empty</a>
+</nav>
+<footer><a href="/legal">Legal</a></footer>
+</body></html>`
+
+func TestLinks(t *testing.T) {
+	asrt := assert.New(t)
+
+	var a struct {
+		Nav     []*url.URL `goquery:"nav,links"`
+		Unique  []url.URL  `goquery:"nav,links,unique"`
+		Strings []string   `goquery:"nav,links,unique"`
+		All     []string   `goquery:"body,links,unique"`
+		None    []string   `goquery:"main,links"`
+	}
+
+	base, _ := url.Parse("https://example.com/blog/post")
+	d := NewDecoder(strings.NewReader(linksPage))
+	d.BaseURL = base
+	asrt.NoError(d.Decode(&a))
+
+	asrt.Len(a.Nav, 4)
+	asrt.Equal("https://example.com/", a.Nav[0].String())
+	asrt.Equal("https://example.com/", a.Nav[3].String())
+	asrt.Len(a.Unique, 3)
+	asrt.Equal("https://example.com/docs/", a.Unique[1].String())
+	asrt.Equal([]string{"https://example.com/", "https://example.com/docs/", "https://example.org/ext"}, a.Strings)
+	asrt.Len(a.All, 4)
+	asrt.Empty(a.None)
+}
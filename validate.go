@@ -0,0 +1,87 @@
+package goq
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/andybalholm/cascadia"
+)
+
+// ValidateOptions controls the optional checks made by ValidateTags.
+type ValidateOptions struct {
+	// DuplicateSelectors reports an error when two fields of the same struct
+	// have identical tags, which is usually a copy and paste mistake.
+	DuplicateSelectors bool
+}
+
+// ValidateTags checks the goquery tags of the type of v and every type nested
+// within it, so that mistakes can be caught at startup rather than when a
+// document is decoded. Element selectors and `regexp:` expressions must
+// compile, and further checks may be enabled with opts. Any error returned will
+// be of type *CannotUnmarshalError.
+func ValidateTags(v interface{}, opts ValidateOptions) error {
+	t := TypeDeref(reflect.TypeOf(v))
+	if t == nil {
+		return &CannotUnmarshalError{V: reflect.ValueOf(v), Reason: nilValue}
+	}
+	return validateType(t, opts, map[reflect.Type]bool{})
+}
+
+func validateType(t reflect.Type, opts ValidateOptions, seen map[reflect.Type]bool) error {
+	t = TypeDeref(t)
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return validateType(t.Elem(), opts, seen)
+	case reflect.Map:
+		if err := validateType(t.Key(), opts, seen); err != nil {
+			return err
+		}
+		return validateType(t.Elem(), opts, seen)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	v := reflect.New(t).Elem()
+	fields := map[goqueryTag]string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := goqueryTag(f.Tag.Get(tagName))
+
+		fail := func(reason string, err error) error {
+			return &CannotUnmarshalError{
+				V:        v,
+				Reason:   reason,
+				Err:      err,
+				Val:      string(tag),
+				FldOrIdx: f.Name,
+			}
+		}
+
+		if tag != "" && tag != ignoreTag {
+			if sel := tag.selector(0); sel != "" {
+				if _, err := cascadia.Compile(sel); err != nil {
+					return fail(invalidSelector, err)
+				}
+			}
+			if _, err := tag.regexp(v); err != nil {
+				return fail(invalidModifier, err)
+			}
+			if other, ok := fields[tag]; ok && opts.DuplicateSelectors {
+				return fail(duplicateSelector, fmt.Errorf("field %s has the same tag", other))
+			}
+			fields[tag] = f.Name
+		}
+
+		if err := validateType(f.Type, opts, seen); err != nil {
+			return fail(invalidTag, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package goq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dupSelectors struct {
+	Title    string `goquery:"h1"`
+	Heading  string `goquery:"h1"`
+	Link     string `goquery:"a,[href]"`
+	LinkText string `goquery:"a"`
+}
+
+func TestValidateTags(t *testing.T) {
+	asrt := assert.New(t)
+
+	asrt.NoError(ValidateTags(&Page{}, ValidateOptions{DuplicateSelectors: true}))
+	asrt.NoError(ValidateTags(MapTest{}, ValidateOptions{DuplicateSelectors: true}))
+	asrt.NoError(ValidateTags(&dupSelectors{}, ValidateOptions{}))
+
+	e := checkErr(asrt, ValidateTags(&dupSelectors{}, ValidateOptions{DuplicateSelectors: true}))
+	asrt.Equal(duplicateSelector, e.Reason)
+	asrt.Equal("Heading", e.FldOrIdx)
+	asrt.Contains(e.Error(), "field Title has the same tag")
+
+	var nested struct {
+		Items []struct {
+			Bad string `goquery:"div[,text"`
+		} `goquery:".item"`
+	}
+	e = checkErr(asrt, ValidateTags(&nested, ValidateOptions{}))
+	asrt.Equal(invalidSelector, e.unwind().last().Reason)
+	asrt.Contains(e.Error(), ".Items.Bad")
+
+	var re struct {
+		Bad string `goquery:"p,regexp:(unclosed"`
+	}
+	e = checkErr(asrt, ValidateTags(&re, ValidateOptions{}))
+	asrt.Equal(invalidModifier, e.unwind().last().Reason)
+}